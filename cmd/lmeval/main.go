@@ -0,0 +1,92 @@
+// Command lmeval evaluates the English lemmatizer against a gold file of tab
+// separated form, POS and lemma lines (see eval.ReadGold).
+//
+// Usage:
+//
+//	lmeval [flags] [gold file]
+//
+// The gold file is read from stdin by default. The flags are:
+//
+//	-k n        the k of the top-k accuracy (5)
+//	-pos        pass the gold POS to the lemmatizer
+//	-errors n   the number of the most frequent errors to list (20)
+//	-rules file use the rule file (see lm.LoadRules) instead of en.MorphRules
+//	-norm       normalize the words with lm.DefaultNormalizer
+//
+// The suffix rules are applied with lm.NewSuffixTrieResolver, i.e. the
+// longest affix first.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timurgarif/nlpgo/en"
+	"github.com/timurgarif/nlpgo/eval"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "lmeval:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("lmeval", flag.ContinueOnError)
+	k := fs.Int("k", 5, "the k of the top-k accuracy")
+	usePos := fs.Bool("pos", false, "pass the gold POS to the lemmatizer")
+	maxErrors := fs.Int("errors", 20, "the number of the most frequent errors to list")
+	rulesFile := fs.String("rules", "", "the rule file to use instead of en.MorphRules")
+	norm := fs.Bool("norm", false, "normalize the words with lm.DefaultNormalizer")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rules := en.MorphRules
+	if *rulesFile != "" {
+		f, err := os.Open(*rulesFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if rules, err = lm.LoadRules(f); err != nil {
+			return fmt.Errorf("%s: %v", *rulesFile, err)
+		}
+	}
+
+	in := stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	gold, err := eval.ReadGold(in)
+	if err != nil {
+		return err
+	}
+
+	var opts []lm.LmOption
+	if *norm {
+		opts = append(opts, lm.WithNormalizer(lm.DefaultNormalizer))
+	}
+	lc := lm.NewLemmaIndex(en.LemmaIdx)
+	lzr := lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(en.ExceptionsIdx),
+		lm.NewSuffixTrieResolver(rules, lc),
+	}, opts...)
+
+	evalOpts := []eval.Option{eval.WithTopK(*k), eval.WithMaxErrors(*maxErrors)}
+	if *usePos {
+		evalOpts = append(evalOpts, eval.WithPosConstraint())
+	}
+
+	_, err = eval.Evaluate(lzr, gold, evalOpts...).WriteTo(stdout)
+	return err
+}
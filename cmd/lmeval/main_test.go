@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGold = "walked\tVBD\twalk\nmice\tNNS\tmouse\nsaw\tVBD\tsee\n"
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	goldFile := filepath.Join(dir, "gold.tsv")
+	assert.NoError(ioutil.WriteFile(goldFile, []byte(testGold), 0644))
+	rulesFile := filepath.Join(dir, "rules.txt")
+	assert.NoError(ioutil.WriteFile(rulesFile, []byte("rule suffix ed pos=VBD\n\ttransform cutoff=2\n"), 0644))
+
+	for _, v := range []struct {
+		msg  string
+		args []string
+		in   string
+		out  []string
+		err  string
+	}{
+		{
+			msg: "gold from stdin",
+			in:  testGold,
+			out: []string{
+				"entries   3\n", "accuracy  0.6667\n", "top-5     1.0000\n",
+				"VBD           2   0.5000   1.0000   0.0000\n",
+				"1      VBD    saw: see -> saw\n",
+			},
+		},
+		{
+			msg:  "gold file, POS constraint and top-1",
+			args: []string{"-pos", "-k", "1", goldFile},
+			out:  []string{"top-1     0.6667\n", "VBD           2   0.5000   0.5000   0.0000\n"},
+		},
+		{
+			msg:  "rule file without the exceptions of the plural",
+			args: []string{"-rules", rulesFile, goldFile},
+			out:  []string{"accuracy  0.6667\n", "1      VBD    saw: see -> saw\n"},
+		},
+		{
+			msg:  "invalid k and errors are ignored",
+			args: []string{"-k", "0", "-errors", "-1"},
+			in:   testGold,
+			out:  []string{"top-5     1.0000\n", "1      VBD    saw: see -> saw\n"},
+		},
+		{
+			msg: "invalid gold file",
+			in:  "walked\twalk\n",
+			err: "line 1: form, POS and lemma expected, got 2 fields",
+		},
+		{
+			msg:  "unknown flag",
+			args: []string{"-x"},
+			err:  "flag provided but not defined: -x",
+		},
+	} {
+		var out bytes.Buffer
+		err := run(v.args, strings.NewReader(v.in), &out)
+		if v.err != "" {
+			assert.EqualError(err, v.err, v.msg)
+			continue
+		}
+		assert.NoError(err, v.msg)
+		for _, s := range v.out {
+			assert.Contains(out.String(), s, v.msg)
+		}
+	}
+}
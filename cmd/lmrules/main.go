@@ -0,0 +1,95 @@
+// Command lmrules works with the lemmatization rule files (see lm.LoadRules).
+//
+// Usage:
+//
+//	lmrules export        write en.MorphRules in the rule file format
+//	lmrules check [file]  validate a rule file (stdin by default)
+//	lmrules lint [file]   report the rule file issues (see lm.LintRules), fails
+//	                      on errors
+//	lmrules induce [file] learn the suffix rules from the tab separated form,
+//	                      lemma and POS lines (see lm.InduceRules) and write
+//	                      them in the rule file format
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timurgarif/nlpgo/en"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "lmrules:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command expected: export, check, lint, induce")
+	}
+
+	switch args[0] {
+	case "export":
+		return lm.WriteRules(stdout, en.MorphRules)
+	case "check":
+		rules, err := loadRules(args[1:], stdin)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%d rules OK\n", len(rules))
+		return nil
+	case "lint":
+		rules, err := loadRules(args[1:], stdin)
+		if err != nil {
+			return err
+		}
+		var errs int
+		for _, v := range lm.LintRules(rules) {
+			fmt.Fprintln(stdout, v)
+			if v.Severity == lm.LintError {
+				errs++
+			}
+		}
+		if errs > 0 {
+			return fmt.Errorf("lint errors: %d", errs)
+		}
+		return nil
+	case "induce":
+		in := stdin
+		if len(args) > 1 {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		pairs, err := lm.ReadWordPairs(in)
+		if err != nil {
+			return err
+		}
+		return lm.WriteRules(stdout, lm.InduceRules(pairs))
+	}
+
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// loadRules loads the rules from the file named by the first arg, or from
+// stdin if no args
+func loadRules(args []string, stdin io.Reader) ([]lm.Rule, error) {
+	if len(args) == 0 {
+		return lm.LoadRules(stdin)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return lm.LoadRules(f)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo/en"
+)
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	var exported bytes.Buffer
+	assert.NoError(run([]string{"export"}, nil, &exported))
+
+	for _, v := range []struct {
+		msg  string
+		args []string
+		in   string
+		out  string
+		err  string
+	}{
+		{
+			msg:  "export -> check round trip",
+			args: []string{"check"},
+			in:   exported.String(),
+			out:  fmt.Sprintf("%d rules OK\n", len(en.MorphRules)),
+		},
+		{
+			msg:  "check fails on an invalid rule file",
+			args: []string{"check"},
+			in:   "rule suffix s pos=XX\n",
+			err:  `line 1: invalid POS "XX"`,
+		},
+		{
+			msg:  "lint reports the warnings only",
+			args: []string{"lint"},
+			in:   "rule suffix s pos=NNS\ntransform cutoff=1\nrule suffix es pos=NNS\ntransform cutoff=2\n",
+			out:  "warning: rule #1 \"es\": affix \"s\" of the preceding rule #0 matches first\n",
+		},
+		{
+			msg:  "induce writes the learned rules",
+			args: []string{"induce"},
+			in:   "walked\twalk\tVBD\ntalked\ttalk\tVBD\n",
+			out:  "rule suffix ed pos=VBD\n\ttransform cutoff=2\n",
+		},
+		{
+			msg: "no command",
+			err: "command expected: export, check, lint, induce",
+		},
+		{
+			msg:  "unknown command",
+			args: []string{"import"},
+			err:  `unknown command "import"`,
+		},
+	} {
+		var out bytes.Buffer
+		err := run(v.args, strings.NewReader(v.in), &out)
+		if v.err != "" {
+			assert.EqualError(err, v.err, v.msg)
+			continue
+		}
+		assert.NoError(err, v.msg)
+		assert.Equal(v.out, out.String(), v.msg)
+	}
+}
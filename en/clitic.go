@@ -0,0 +1,244 @@
+package en
+
+import (
+	"strings"
+
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+// CliticPart is a part of a token split by CliticResolver, e.g. "do" or "n't"
+// of "don't"
+type CliticPart struct {
+	// The part of the token
+	Text string
+	// The byte offsets of the part in the token
+	Start, End int
+	// Whether the part is a clitic, not a host word
+	Clitic bool
+	// The lemma candidates, e.g. "would", "have" for "'d"
+	Lemmata []lm.Lemma
+}
+
+var (
+	lemmaNot   = lm.Lemma{Val: "not", Pos: []nlpgo.POSId{nlpgo.PosIdAdv}}
+	lemmaWill  = lm.Lemma{Val: "will", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaWould = lm.Lemma{Val: "would", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaShall = lm.Lemma{Val: "shall", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaCan   = lm.Lemma{Val: "can", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaBe    = lm.Lemma{Val: "be", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaHave  = lm.Lemma{Val: "have", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+	lemmaUs    = lm.Lemma{Val: "us", Pos: []nlpgo.POSId{nlpgo.PosIdPron}}
+	lemmaYou   = lm.Lemma{Val: "you", Pos: []nlpgo.POSId{nlpgo.PosIdPron}}
+	lemmaIt    = lm.Lemma{Val: "it", Pos: []nlpgo.POSId{nlpgo.PosIdPron}}
+	// The possessive marker
+	lemmaPoss = lm.Lemma{Val: "'s"}
+)
+
+// The clitics attached to the host word end, the apostrophe is ASCII
+var cliticSuffixes = []struct {
+	sfx     string
+	lemmata []lm.Lemma
+}{
+	{"n't", []lm.Lemma{lemmaNot}},
+	{"'ll", []lm.Lemma{lemmaWill}},
+	{"'re", []lm.Lemma{lemmaBe}},
+	{"'ve", []lm.Lemma{lemmaHave}},
+	{"'m", []lm.Lemma{lemmaBe}},
+	{"'d", []lm.Lemma{lemmaWould, lemmaHave}},
+	// The lemmata depend on the host, see cliticS
+	{"'s", nil},
+	// The plural possessive, e.g. "students'"
+	{"'", []lm.Lemma{lemmaPoss}},
+}
+
+// The hosts of "n't" which are not words, e.g. "can't" -> "ca" + "n't"
+var negHosts = map[string]lm.Lemma{
+	"ca":  lemmaCan,
+	"wo":  lemmaWill,
+	"sha": lemmaShall,
+	"ai":  lemmaBe,
+}
+
+// The hosts whose "'s" is "is" or "has", not the possessive
+var sHosts = map[string]bool{
+	"it": true, "he": true, "she": true, "that": true, "this": true, "there": true, "here": true,
+	"what": true, "who": true, "where": true, "when": true, "why": true, "how": true,
+}
+
+// The tokens split as a whole
+var cliticTokens = map[string][]CliticPart{
+	"y'all": {
+		{Text: "y'", Lemmata: []lm.Lemma{lemmaYou}},
+		{Text: "all", Lemmata: []lm.Lemma{{Val: "all", Pos: []nlpgo.POSId{nlpgo.PosIdPron}}}},
+	},
+	"'tis": {
+		{Text: "'t", Lemmata: []lm.Lemma{lemmaIt}},
+		{Text: "is", Clitic: true, Lemmata: []lm.Lemma{lemmaBe}},
+	},
+	"'twas": {
+		{Text: "'t", Lemmata: []lm.Lemma{lemmaIt}},
+		{Text: "was", Clitic: true, Lemmata: []lm.Lemma{lemmaBe}},
+	},
+}
+
+// CliticResolver splits the English tokens with clitics, possessives and
+// contractions into the host word and the clitics, e.g. "don't" -> "do" +
+// "n't", "we'll" -> "we" + "'ll", "students'" -> "students" + "'". The host
+// words are lemmatized by the Lemmatizer given.
+type CliticResolver struct {
+	lzr *lm.Lemmatizer
+}
+
+// NewCliticResolver creates a CliticResolver lemmatizing the host words with
+// lzr, which should not have the CliticResolver among its resolvers.
+func NewCliticResolver(lzr *lm.Lemmatizer) *CliticResolver {
+	return &CliticResolver{lzr: lzr}
+}
+
+// Split returns the parts of the token in their order with the lemma
+// candidates, nil if the token has no clitic. Both the ASCII and typographic
+// (U+2019) apostrophes are recognized.
+//
+// The host word lemmata are the Lemmatizer candidates of the lower case host
+// or the host itself if there are none, e.g. "I". The "'s" clitic is "is" or "has" after the
+// pronouns and wh-words like "it" or "what", "us" after "let", and the
+// possessive marker (lemma "'s" without POS) otherwise.
+func (r *CliticResolver) Split(token string) []CliticPart {
+	// The ASCII apostrophe and lower case copy to match, the offsets differ
+	// for the typographic apostrophe only
+	norm := strings.Replace(token, "’", "'", -1)
+	offsets := make([]int, 0, len(norm)+1)
+	for i := 0; i < len(token); {
+		offsets = append(offsets, i)
+		if strings.HasPrefix(token[i:], "’") {
+			i += len("’")
+		} else {
+			i++
+		}
+	}
+	offsets = append(offsets, len(token))
+	norm = lowerASCII(norm)
+
+	if pp, ok := cliticTokens[norm]; ok {
+		parts := make([]CliticPart, len(pp))
+		start := 0
+		for i, p := range pp {
+			end := start + len(p.Text)
+			p.Text, p.Start, p.End = token[offsets[start]:offsets[end]], offsets[start], offsets[end]
+			parts[i] = p
+			start = end
+		}
+		return parts
+	}
+
+	// Detach the clitics from the end, e.g. "shouldn't've"
+	var clitics []CliticPart
+	// Whether "n't" is attached to the host
+	var neg bool
+	end := len(norm)
+	for end > 0 {
+		host := norm[:end]
+		var found bool
+		for _, cs := range cliticSuffixes {
+			if !strings.HasSuffix(host, cs.sfx) || len(host) == len(cs.sfx) {
+				continue
+			}
+			// The possessive ones end the token
+			if (cs.sfx == "'s" || cs.sfx == "'") && len(clitics) > 0 {
+				continue
+			}
+			rest := host[:len(host)-len(cs.sfx)]
+			if cs.sfx == "'" && !strings.HasSuffix(rest, "s") {
+				continue
+			}
+
+			ll := cs.lemmata
+			if cs.sfx == "'s" {
+				ll = cliticS(rest)
+			}
+			clitics = append(clitics, CliticPart{Start: len(rest), End: end, Clitic: true, Lemmata: ll})
+			neg = cs.sfx == "n't"
+			end = len(rest)
+			found = true
+			break
+		}
+		if !found {
+			break
+		}
+	}
+	if len(clitics) == 0 {
+		return nil
+	}
+
+	host := CliticPart{Text: token[:offsets[end]], End: offsets[end]}
+	if l, ok := negHosts[norm[:end]]; ok && neg {
+		host.Lemmata = []lm.Lemma{l}
+	} else {
+		// The clitics are matched case-insensitively, so is the host, e.g.
+		// "Don't"
+		host.Lemmata = r.lzr.LemmaCandidates(norm[:end], 0)
+		if len(host.Lemmata) == 0 {
+			host.Lemmata = []lm.Lemma{{Val: host.Text}}
+		}
+	}
+
+	parts := []CliticPart{host}
+	for i := len(clitics) - 1; i >= 0; i-- {
+		c := clitics[i]
+		c.Start, c.End = offsets[c.Start], offsets[c.End]
+		c.Text = token[c.Start:c.End]
+		parts = append(parts, c)
+	}
+
+	return parts
+}
+
+// cliticS returns the lemmata of "'s" after the host
+func cliticS(host string) []lm.Lemma {
+	switch {
+	case host == "let":
+		return []lm.Lemma{lemmaUs}
+	case sHosts[host]:
+		return []lm.Lemma{lemmaBe, lemmaHave}
+	}
+	return []lm.Lemma{lemmaPoss}
+}
+
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// Resolve adds the lemmata of the first host word of the token if it has
+// clitics, e.g. "student" for "students'", so the CliticResolver can be used
+// as an lm.LmResolver too. The candidates are added with lm.SourceClitic.
+func (r *CliticResolver) Resolve(word string, acc *lm.LemmaAccumulator, max int) {
+	for _, p := range r.Split(word) {
+		if p.Clitic {
+			continue
+		}
+
+		for _, l := range p.Lemmata {
+			pp, ok := acc.FilterPos(l.Pos)
+			if !ok {
+				continue
+			}
+			acc.Add(lm.Candidate{
+				Lemma:     lm.Lemma{Val: l.Val, Pos: pp},
+				Source:    lm.SourceClitic,
+				Transform: -1,
+				Score:     lm.ScoreClitic,
+			})
+			if acc.Len() >= max {
+				return
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,97 @@
+package en
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func TestCliticSplit(t *testing.T) {
+	assert := assert.New(t)
+
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	cr := NewCliticResolver(lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lc),
+	}))
+
+	type part struct {
+		text       string
+		start, end int
+		lemmata    []string
+	}
+	split := func(token string) (pp []part) {
+		for _, p := range cr.Split(token) {
+			var ll []string
+			for _, l := range p.Lemmata {
+				ll = append(ll, l.Val)
+			}
+			assert.Equal(p.Text, token[p.Start:p.End], token)
+			pp = append(pp, part{p.Text, p.Start, p.End, ll})
+		}
+		return
+	}
+
+	assert.Equal([]part{{"do", 0, 2, []string{"do"}}, {"n't", 2, 5, []string{"not"}}}, split("don't"))
+	assert.Equal([]part{{"does", 0, 4, []string{"do"}}, {"n't", 4, 7, []string{"not"}}}, split("doesn't"))
+	assert.Equal([]part{{"ca", 0, 2, []string{"can"}}, {"n't", 2, 5, []string{"not"}}}, split("can't"))
+	assert.Equal([]part{{"Wo", 0, 2, []string{"will"}}, {"n't", 2, 5, []string{"not"}}}, split("Won't"))
+	assert.Equal([]part{{"we", 0, 2, []string{"we"}}, {"'ll", 2, 5, []string{"will"}}}, split("we'll"))
+	assert.Equal([]part{{"I", 0, 1, []string{"I"}}, {"'d", 1, 3, []string{"would", "have"}}}, split("I'd"))
+	assert.Equal([]part{{"they", 0, 4, []string{"they"}}, {"'re", 4, 7, []string{"be"}}}, split("they're"))
+	assert.Equal([]part{
+		{"should", 0, 6, []string{"should"}},
+		{"n't", 6, 9, []string{"not"}},
+		{"'ve", 9, 12, []string{"have"}},
+	}, split("shouldn't've"))
+	assert.Equal([]part{{"Smyth", 0, 5, []string{"Smyth"}}, {"'s", 5, 7, []string{"'s"}}}, split("Smyth's"))
+	assert.Equal([]part{{"Do", 0, 2, []string{"do"}}, {"n't", 2, 5, []string{"not"}}}, split("Don't"))
+	assert.Equal([]part{{"DOES", 0, 4, []string{"do"}}, {"N'T", 4, 7, []string{"not"}}}, split("DOESN'T"))
+	assert.Equal([]part{{"it", 0, 2, []string{"it"}}, {"'s", 2, 4, []string{"be", "have"}}}, split("it's"))
+	assert.Equal([]part{{"let", 0, 3, []string{"let"}}, {"'s", 3, 5, []string{"us"}}}, split("let's"))
+	assert.Equal([]part{{"students", 0, 8, []string{"student"}}, {"'", 8, 9, []string{"'s"}}}, split("students'"))
+	assert.Equal([]part{{"y'", 0, 2, []string{"you"}}, {"all", 2, 5, []string{"all"}}}, split("y'all"))
+
+	// The typographic apostrophe
+	assert.Equal([]part{{"do", 0, 2, []string{"do"}}, {"n’t", 2, 7, []string{"not"}}}, split("don’t"))
+	assert.Equal([]part{{"y’", 0, 4, []string{"you"}}, {"all", 4, 7, []string{"all"}}}, split("y’all"))
+
+	pp := cr.Split("don't")
+	assert.Equal([]bool{false, true}, []bool{pp[0].Clitic, pp[1].Clitic})
+	pp = cr.Split("y'all")
+	assert.Equal([]bool{false, false}, []bool{pp[0].Clitic, pp[1].Clitic})
+
+	for _, w := range []string{"walked", "o'clock", "rock'n'roll", "'s", "'"} {
+		assert.Nil(cr.Split(w), w)
+	}
+	assert.Equal([]part{{"boss", 0, 4, []string{"boss"}}, {"'", 4, 5, []string{"'s"}}}, split("boss'"))
+	assert.Nil(cr.Split("dog'"), "The bare apostrophe follows the plural only")
+}
+
+func TestCliticResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	resolvers := []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lc),
+	}
+	lzr := lm.NewLemmatizer(lc, append(resolvers, NewCliticResolver(lm.NewLemmatizer(lc, resolvers))))
+
+	assert.Equal([]lm.Candidate{
+		{
+			Lemma:     lm.Lemma{Val: "student", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			Surface:   "students'",
+			Source:    lm.SourceClitic,
+			Resolver:  2,
+			Transform: -1,
+			Score:     lm.ScoreClitic,
+		},
+	}, lzr.Candidates("students'", 5))
+	assert.Equal("do", lzr.Lemmatize("don't").Val)
+	assert.Equal("you", lzr.Lemmatize("y'all").Val)
+	assert.Empty(lzr.LemmaCandidatesFor("don't", []nlpgo.POSId{nlpgo.PosIdAdj}, 5))
+	assert.Equal("clitic", lm.SourceClitic.String())
+}
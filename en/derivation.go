@@ -0,0 +1,237 @@
+package en
+
+import (
+	"regexp"
+
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+// The derivation types of DerivRules
+const (
+	DerivIsh   = "ish"
+	DerivNess  = "ness"
+	DerivLy    = "ly"
+	DerivAble  = "able"
+	DerivLess  = "less"
+	DerivFul   = "ful"
+	DerivAgent = "agent"
+	DerivIze   = "ize"
+)
+
+// DerivRules link the words derived with the productive suffixes to their
+// base lemmata, e.g. "wolfish" -> "wolf". Use them with
+// lm.NewDerivationResolver, they are not a part of MorphRules.
+var DerivRules = []lm.DerivRule{
+	{
+		// wolfish -> wolf
+		Type:       DerivIsh,
+		DerivedPos: nlpgo.PosIdAdj,
+		Rule: lm.Rule{
+			Affix: "ish",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNoun, nlpgo.PosIdAdj},
+			Transforms: []lm.RuleTransform{
+				{
+					// reddish -> red
+					Cutoff:   4,
+					ReBefore: regexp.MustCompile(vowel + doubleConnosantButWX + `ish$`),
+				},
+				{
+					Cutoff:      3,
+					MinValidLen: 5,
+				},
+				{
+					// largish -> large
+					Cutoff:      3,
+					Augment:     "e",
+					MinValidLen: 5,
+				},
+			},
+		},
+	},
+	{
+		// kindness -> kind
+		Type:       DerivNess,
+		DerivedPos: nlpgo.PosIdNoun,
+		Rule: lm.Rule{
+			Affix: "ness",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdAdj},
+			Transforms: []lm.RuleTransform{
+				{
+					// happiness -> happy
+					Cutoff:   5,
+					ReBefore: regexp.MustCompile(`.iness$`),
+					Augment:  "y",
+				},
+				{
+					Cutoff:      4,
+					MinValidLen: 6,
+				},
+			},
+		},
+	},
+	{
+		// quickly -> quick
+		Type:       DerivLy,
+		DerivedPos: nlpgo.PosIdAdv,
+		Rule: lm.Rule{
+			Affix: "ly",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdAdj},
+			Transforms: []lm.RuleTransform{
+				{
+					// happily -> happy
+					Cutoff:   3,
+					ReBefore: regexp.MustCompile(`.ily$`),
+					Augment:  "y",
+				},
+				{
+					// basically -> basic
+					Cutoff:   4,
+					ReBefore: regexp.MustCompile(`.ically$`),
+				},
+				{
+					// gently -> gentle
+					Cutoff:   1,
+					ReBefore: regexp.MustCompile(connosant + `ly$`),
+					Augment:  "e",
+				},
+				{
+					Cutoff:      2,
+					MinValidLen: 5,
+				},
+				{
+					// truly -> true
+					Cutoff:      2,
+					Augment:     "e",
+					MinValidLen: 4,
+				},
+			},
+		},
+	},
+	{
+		// readable -> read
+		Type:       DerivAble,
+		DerivedPos: nlpgo.PosIdAdj,
+		Rule: lm.Rule{
+			Affix: "able",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVerb},
+			Transforms: []lm.RuleTransform{
+				{
+					// reliable -> rely
+					Cutoff:   5,
+					ReBefore: regexp.MustCompile(`.iable$`),
+					Augment:  "y",
+				},
+				{
+					// forgettable -> forget
+					Cutoff:   5,
+					ReBefore: regexp.MustCompile(vowel + doubleConnosantButWX + `able$`),
+				},
+				{
+					Cutoff:      4,
+					MinValidLen: 6,
+				},
+				{
+					// lovable -> love
+					Cutoff:      4,
+					Augment:     "e",
+					MinValidLen: 6,
+				},
+			},
+		},
+	},
+	{
+		// homeless -> home
+		Type:       DerivLess,
+		DerivedPos: nlpgo.PosIdAdj,
+		Rule: lm.Rule{
+			Affix: "less",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNoun},
+			Transforms: []lm.RuleTransform{
+				{
+					// penniless -> penny
+					Cutoff:   5,
+					ReBefore: regexp.MustCompile(`.iless$`),
+					Augment:  "y",
+				},
+				{
+					Cutoff:      4,
+					MinValidLen: 6,
+				},
+			},
+		},
+	},
+	{
+		// hopeful -> hope
+		Type:       DerivFul,
+		DerivedPos: nlpgo.PosIdAdj,
+		Rule: lm.Rule{
+			Affix: "ful",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNoun},
+			Transforms: []lm.RuleTransform{
+				{
+					// beautiful -> beauty
+					Cutoff:   4,
+					ReBefore: regexp.MustCompile(`.iful$`),
+					Augment:  "y",
+				},
+				{
+					Cutoff:      3,
+					MinValidLen: 5,
+				},
+			},
+		},
+	},
+	{
+		// teacher -> teach
+		Type:       DerivAgent,
+		DerivedPos: nlpgo.PosIdNoun,
+		Rule: lm.Rule{
+			Affix: "er",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVerb},
+			Transforms: []lm.RuleTransform{
+				{
+					// carrier -> carry
+					Cutoff:   3,
+					ReBefore: regexp.MustCompile(`.ier$`),
+					Augment:  "y",
+				},
+				{
+					// runner -> run
+					Cutoff:   3,
+					ReBefore: regexp.MustCompile(vowel + doubleConnosantButWX + `er$`),
+				},
+				{
+					Cutoff:      2,
+					MinValidLen: 5,
+				},
+				{
+					// writer -> write
+					Cutoff:      1,
+					MinValidLen: 4,
+				},
+			},
+		},
+	},
+	{
+		// modernize -> modern
+		Type:       DerivIze,
+		DerivedPos: nlpgo.PosIdVerb,
+		Rule: lm.Rule{
+			Affix: "ize",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNoun, nlpgo.PosIdAdj},
+			Transforms: []lm.RuleTransform{
+				{
+					Cutoff:      3,
+					MinValidLen: 6,
+				},
+				{
+					// apologize -> apology
+					Cutoff:      3,
+					Augment:     "y",
+					MinValidLen: 6,
+				},
+			},
+		},
+	},
+}
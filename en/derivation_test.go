@@ -0,0 +1,84 @@
+package en
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func TestDerivRules(t *testing.T) {
+	assert := assert.New(t)
+
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	lzr := lm.NewLemmatizer(lc, []lm.LmResolver{lm.NewDerivationResolver(DerivRules, lc)},
+		lm.WithMaxCandidates(10))
+
+	for _, v := range []struct {
+		in, out string
+		typ     string
+		pos     nlpgo.POSId
+	}{
+		{"wiseish", "wise", DerivIsh, nlpgo.PosIdAdj},
+		{"wolfish", "wolf", DerivIsh, nlpgo.PosIdAdj},
+		{"reddish", "red", DerivIsh, nlpgo.PosIdAdj},
+		{"largish", "large", DerivIsh, nlpgo.PosIdAdj},
+		{"kindness", "kind", DerivNess, nlpgo.PosIdNoun},
+		{"happiness", "happy", DerivNess, nlpgo.PosIdNoun},
+		{"quickly", "quick", DerivLy, nlpgo.PosIdAdv},
+		{"happily", "happy", DerivLy, nlpgo.PosIdAdv},
+		{"basically", "basic", DerivLy, nlpgo.PosIdAdv},
+		{"gently", "gentle", DerivLy, nlpgo.PosIdAdv},
+		{"truly", "true", DerivLy, nlpgo.PosIdAdv},
+		{"readable", "read", DerivAble, nlpgo.PosIdAdj},
+		{"lovable", "love", DerivAble, nlpgo.PosIdAdj},
+		{"forgettable", "forget", DerivAble, nlpgo.PosIdAdj},
+		{"reliable", "rely", DerivAble, nlpgo.PosIdAdj},
+		{"homeless", "home", DerivLess, nlpgo.PosIdAdj},
+		{"penniless", "penny", DerivLess, nlpgo.PosIdAdj},
+		{"hopeful", "hope", DerivFul, nlpgo.PosIdAdj},
+		{"beautiful", "beauty", DerivFul, nlpgo.PosIdAdj},
+		{"teacher", "teach", DerivAgent, nlpgo.PosIdNoun},
+		{"writer", "write", DerivAgent, nlpgo.PosIdNoun},
+		{"runner", "run", DerivAgent, nlpgo.PosIdNoun},
+		{"carrier", "carry", DerivAgent, nlpgo.PosIdNoun},
+		{"modernize", "modern", DerivIze, nlpgo.PosIdVerb},
+		{"apologize", "apology", DerivIze, nlpgo.PosIdVerb},
+		{"memorize", "memory", DerivIze, nlpgo.PosIdVerb},
+	} {
+		var found bool
+		for _, c := range lzr.Candidates(v.in, 0) {
+			if c.Source != lm.SourceDerivation {
+				continue
+			}
+			found = true
+			assert.Equal(v.out, c.Val, v.in)
+			assert.Equal(v.typ, c.Derivation, v.in)
+			assert.Equal([]nlpgo.POSId{v.pos}, c.Pos, v.in)
+			break
+		}
+		assert.True(found, v.in)
+	}
+
+	// The derived word itself is a lemma, so it comes first
+	assert.Equal([]lm.Lemma{
+		{Val: "gently", Pos: []nlpgo.POSId{nlpgo.PosIdAdv}},
+		{Val: "gentle", Pos: []nlpgo.POSId{nlpgo.PosIdAdv}},
+	}, lzr.LemmaCandidatesFor("gently", []nlpgo.POSId{nlpgo.PosIdAdv}, 5))
+	// The derived word POS is constrained
+	assert.Empty(lzr.LemmaCandidatesFor("sadness", []nlpgo.POSId{nlpgo.PosIdVerb}, 5))
+
+	// "jokey" is not in LemmaIdx, the base lemma must be known
+	assert.Empty(lzr.LemmaCandidates("jokeyish", 5))
+	jokeyIdx := lm.NewLemmaIndex(map[string][]nlpgo.POSId{"jokey": {nlpgo.PosIdAdj}})
+	jokey := lm.NewLemmatizer(jokeyIdx, []lm.LmResolver{lm.NewDerivationResolver(DerivRules, jokeyIdx)})
+	assert.Equal([]lm.Lemma{{Val: "jokey", Pos: []nlpgo.POSId{nlpgo.PosIdAdj}}}, jokey.LemmaCandidates("jokeyish", 5))
+
+	// The derivations are opt-in
+	inflectional := lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lc),
+	})
+	assert.Empty(inflectional.LemmaCandidates("wiseish", 5))
+}
@@ -2138,7 +2138,7 @@ var ExceptionsIdx = map[string][]lm.Lemma{
 	"leariest":          {{Val: "leary", Pos: p{41}}},
 	"learned":           {{Val: "learn", Pos: p{44, 45}}},
 	"learnt":            {{Val: "learn", Pos: p{44, 45}}},
-	"leaves":            {{Val: "leave", Pos: p{30}}, {Val: "leaf", Pos: p{30}}},
+	"leaves":            {{Val: "leave", Pos: p{30, 48}}, {Val: "leaf", Pos: p{30}}},
 	"led":               {{Val: "lead", Pos: p{45, 44}}},
 	"leerier":           {{Val: "leery", Pos: p{40}}},
 	"leeriest":          {{Val: "leery", Pos: p{41}}},
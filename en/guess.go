@@ -0,0 +1,139 @@
+package en
+
+import "strings"
+
+// The consonant clusters starting the English words
+var validOnsets = toSet(
+	"bl br ch chr cl cr dr dw fl fr gh gl gn gr kl kn kr ph phr pl pr ps qu rh " +
+		"sc sch scr sh shr sk sl sm sn sp sph spl spr squ st str sv sw " +
+		"th thr tr ts tw wh wr")
+
+// The consonant clusters ending the English words. The single consonants but
+// "j", "q" and "v" are valid as well.
+var validCodas = toSet(
+	"bt ch ck ct dth ff ft gh ght ld lf lk ll lm ln lp ls lsh lt lth mb mn mp mph " +
+		"nc nch nd ng nk ns nt nth nx ph pt rb rc rch rd rf rg rk rl rm rn rp rs " +
+		"rsh rst rt rth sc sh sk sm sp ss st tch th ts wl wn ws xt zz")
+
+func toSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range strings.Fields(s) {
+		set[v] = true
+	}
+	return set
+}
+
+// MinGuessLen is the minimal length of a plausible lemma guess
+const MinGuessLen = 3
+
+// Plausibility scores how much the lemma candidate looks like an English word
+// in the [0, 1] range. It's to be used with lm.NewGuessResolver to guess the
+// lemmata of the unknown words with MorphRules, e.g. "tweeting" -> "tweet"
+// scores higher than "tweete". The candidate scores 0 if it's shorter than
+// MinGuessLen, has no vowel or three same letters in a row. It's scored down
+// if:
+//   - it starts or ends with an unusual consonant cluster, e.g. "hopp"
+//   - it ends with a silent "e" after a vowel pair or a consonant cluster,
+//     e.g. "zoome", "texte"
+//   - it ends with "i" after a consonant, e.g. "tri"
+//   - it has non a-z letters
+func Plausibility(lemma string) float64 {
+	if len([]rune(lemma)) < MinGuessLen {
+		return 0
+	}
+
+	p := 1.0
+	for i := 0; i < len(lemma); i++ {
+		c := lemma[i]
+		if (c < 'a' || c > 'z') && c != '-' && c != '\'' {
+			p *= 0.5
+			break
+		}
+	}
+
+	for i := 2; i < len(lemma); i++ {
+		if lemma[i] == lemma[i-1] && lemma[i] == lemma[i-2] {
+			return 0
+		}
+	}
+
+	// The first vowel and the start of the final consonant cluster
+	first := strings.IndexAny(lemma, "aeiouy")
+	if first == 0 && lemma[0] == 'y' {
+		first = strings.IndexAny(lemma[1:], "aeiouy")
+		if first >= 0 {
+			first++
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	last := strings.LastIndexAny(lemma, "aeiouy") + 1
+
+	if onset := lemma[:first]; len(onset) > 1 && !validOnsets[onset] {
+		p *= 0.2
+	}
+
+	coda := lemma[last:]
+	switch {
+	case coda == "":
+		p *= vowelEnding(lemma)
+	case len(coda) == 1:
+		if strings.ContainsAny(coda, "jqv") {
+			p *= 0.2
+		}
+	case !validCodas[coda]:
+		if len(coda) == 2 && coda[0] == coda[1] {
+			// A double consonant is rare, e.g. "add", "egg"
+			p *= 0.3
+		} else {
+			p *= 0.2
+		}
+	}
+
+	return p
+}
+
+// vowelEnding scores the word ending with a vowel
+func vowelEnding(w string) float64 {
+	n := len(w)
+	// Two vowels, e.g. "agree", "blue", "tattoo"
+	if isGuessVowel(w[n-2]) {
+		return 1
+	}
+
+	switch w[n-1] {
+	case 'e':
+	case 'i':
+		// A consonant and "i" is rare, e.g. "taxi"
+		return 0.5
+	default:
+		return 1
+	}
+
+	// A consonant and silent "e"
+	if n >= 3 && w[n-2] == w[n-3] {
+		// After a double consonant, e.g. "hoppe"
+		return 0.3
+	}
+	if strings.ContainsAny(w[n-2:n-1], "cgsvz") || strings.HasSuffix(w, "the") || strings.HasSuffix(w, "le") {
+		// The "e" softens the consonant, e.g. "peace", "leave", "loose"
+		return 1
+	}
+	// After a consonant cluster, e.g. "texte", but "paste", "acre"
+	i := n - 3
+	if i >= 0 && !isGuessVowel(w[i]) && w[n-2] != 'r' && !strings.HasSuffix(w, "ste") {
+		return 0.3
+	}
+	// After a vowel pair, e.g. "zoome", but after "gu" and "qu", e.g. "guide"
+	if i >= 1 && isGuessVowel(w[i]) && isGuessVowel(w[i-1]) &&
+		!(w[i-1] == 'u' && i >= 2 && (w[i-2] == 'g' || w[i-2] == 'q')) {
+		return 0.3
+	}
+
+	return 1
+}
+
+func isGuessVowel(c byte) bool {
+	return strings.IndexByte("aeiouy", c) >= 0
+}
@@ -0,0 +1,79 @@
+package en
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func TestPlausibility(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, w := range []string{"tweet", "zoom", "text", "stretch", "agree", "try", "vape", "guide", "leave", "paste", "table"} {
+		assert.Equal(1.0, Plausibility(w), w)
+	}
+
+	for _, v := range []struct {
+		better, worse string
+	}{
+		{"tweet", "tweete"},
+		{"zoom", "zoome"},
+		{"text", "texte"},
+		{"hop", "hoppe"},
+		{"hop", "hopp"},
+		{"try", "tri"},
+		{"blog", "vlog"},
+		{"skype", "skypé"},
+	} {
+		assert.Greater(Plausibility(v.better), Plausibility(v.worse), v.worse)
+		assert.Greater(Plausibility(v.worse), 0.0, v.worse)
+	}
+
+	for _, w := range []string{"tw", "pwn", "zzzt"} {
+		assert.Equal(0.0, Plausibility(w), w)
+	}
+}
+
+func TestGuess(t *testing.T) {
+	assert := assert.New(t)
+
+	// No dictionary at all
+	l := lm.NewLemmatizer(lm.LmCheckerFunc(func(string) lm.Lemma { return lm.Lemma{} }),
+		[]lm.LmResolver{lm.NewGuessResolver(MorphRules, Plausibility)})
+
+	for _, v := range []struct {
+		in, out string
+	}{
+		{"zoomed", "zoom"},
+		{"tweeting", "tweet"},
+		{"texted", "text"},
+		{"unfriended", "unfriend"},
+		{"photoshopped", "photoshop"},
+		{"fracking", "frack"},
+		{"vaping", "vape"},
+		{"selfies", "selfie"},
+		{"tried", "try"},
+	} {
+		cc := l.Candidates(v.in, 5)
+		if assert.NotEmpty(cc, v.in) {
+			assert.Equal(v.out, cc[0].Val, v.in)
+			assert.Equal(lm.SourceGuess, cc[0].Source, v.in)
+			assert.True(cc[0].Score <= lm.ScoreGuess, v.in)
+		}
+	}
+
+	assert.Empty(l.Candidates("zzzing", 5))
+
+	// The fallback after the dictionary resolvers
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	l = lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lc),
+		lm.NewGuessResolver(MorphRules, Plausibility),
+	})
+	cc := l.Candidates("zoomed", 5)
+	assert.Len(cc, 1)
+	assert.Equal(lm.SourceRule, cc[0].Source)
+	assert.Equal("unfriend", l.Lemmatize("unfriended").Val)
+}
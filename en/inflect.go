@@ -0,0 +1,390 @@
+package en
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+// irregular is an inflected form from ExceptionsIdx
+type irregular struct {
+	form string
+	pos  []nlpgo.POSId
+}
+
+var (
+	inflectOnce sync.Once
+	// The ExceptionsIdx inverted: lemma -> inflected forms
+	irregulars map[string][]irregular
+	// Lemmatizes the generated forms with MorphRules only
+	inflectCheck *lm.Lemmatizer
+)
+
+// The ExceptionsIdx spellings which are lemmatized but not generated as the
+// regular forms are in use, e.g. "taxis", "taxiing", "singeing"
+var rareSpellings = map[string]bool{
+	"singing": true,
+	"taxies":  true,
+	"taxying": true,
+}
+
+// The lemma and plural endings of the classical plurals, the regular ones are
+// in use too, e.g. "foci", "focuses"
+var classicalEndings = [][2]string{
+	{"us", "i"},
+	{"a", "ae"},
+	{"um", "a"},
+	{"ex", "ices"},
+	{"ix", "ices"},
+	{"o", "i"},
+	{"eau", "eaux"},
+}
+
+func initInflect() {
+	irregulars = make(map[string][]irregular)
+	for form, ll := range ExceptionsIdx {
+		for _, l := range ll {
+			irregulars[l.Val] = append(irregulars[l.Val], irregular{form: form, pos: l.Pos})
+		}
+	}
+
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	none := lm.LmCheckerFunc(func(string) lm.Lemma { return lm.Lemma{} })
+	inflectCheck = lm.NewLemmatizer(none, []lm.LmResolver{lm.NewSuffixTrieResolver(MorphRules, lc)})
+}
+
+// Inflect returns the inflected forms of the lemma for the POS form, e.g.
+// "walk", nlpgo.PosIdVbd -> "walked", or nil if LemmaIdx has no such lemma of
+// the POS the form belongs to (see nlpgo.POSId.HasForm).
+//
+// The irregular forms come from ExceptionsIdx but a few rare spellings (see
+// rareSpellings), all the variants are returned in the alphabetical order,
+// e.g. "learned", "learnt". The classical plurals come with the regular one,
+// e.g. "foci", "focuses". ExceptionsIdx is inverted on the first call, so its
+// later changes are not seen.
+// Otherwise the regular form is generated by MorphRules in reverse: the
+// transforms of the rules for the form (the longer affix and cutoff first)
+// yield the form hypotheses which are kept only if MorphRules lemmatize them
+// back to the lemma and no other lemma takes them (see takenForm). The first
+// confirmed one is returned, e.g. "hop" -> "hopping" as "hoping" is
+// lemmatized to "hope". The adjectives of more syllables than "happy" have no
+// regular comparison, e.g. "careful". Nil is returned if there is no rule for
+// the form, e.g. for RBR.
+func Inflect(lemma string, form nlpgo.POSId) []string {
+	if !hasPosFor(lemma, form) {
+		return nil
+	}
+
+	inflectOnce.Do(initInflect)
+
+	var ff []string
+	for _, irr := range irregulars[lemma] {
+		if hasPos(irr.pos, form) && !rareSpellings[irr.form] {
+			ff = append(ff, irr.form)
+		}
+	}
+	if len(ff) > 0 {
+		if form == nlpgo.PosIdNns && classicalPlural(lemma, ff) {
+			if f := regularForm(lemma, form); f != "" {
+				ff = append(ff, f)
+			}
+		}
+		sort.Strings(ff)
+		return ff
+	}
+
+	// The present tense non-3rd person is the lemma itself
+	if form == nlpgo.PosIdVbp {
+		return []string{lemma}
+	}
+
+	if f := regularForm(lemma, form); f != "" {
+		return []string{f}
+	}
+
+	return nil
+}
+
+// classicalPlural reports whether the plurals of the lemma are the classical
+// ones only
+func classicalPlural(lemma string, ff []string) bool {
+	for _, f := range ff {
+		classical := false
+		for _, e := range classicalEndings {
+			if strings.HasSuffix(lemma, e[0]) && f == lemma[:len(lemma)-len(e[0])]+e[1] {
+				classical = true
+				break
+			}
+		}
+		if !classical {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPosFor reports whether the lemma has a POS which the form belongs to
+func hasPosFor(lemma string, form nlpgo.POSId) bool {
+	for _, p := range LemmaIdx[lemma] {
+		if p.HasForm(form) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPos(pp []nlpgo.POSId, pos nlpgo.POSId) bool {
+	for _, p := range pp {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// regularForm generates the form by MorphRules, empty string if none
+func regularForm(lemma string, form nlpgo.POSId) string {
+	if (form == nlpgo.PosIdJjr || form == nlpgo.PosIdJjs) && !comparable(lemma) {
+		return ""
+	}
+
+	type transform struct {
+		r  *lm.Rule
+		rt *lm.RuleTransform
+	}
+
+	// The more specific changes first: the longer affixes and cutoffs
+	var tt []transform
+	for i := range MorphRules {
+		r := &MorphRules[i]
+		if r.Kind != lm.AffixSuffix || !hasPos(r.Pos, form) {
+			continue
+		}
+		for ti := range r.Transforms {
+			tt = append(tt, transform{r, &r.Transforms[ti]})
+		}
+	}
+	sort.SliceStable(tt, func(i, j int) bool {
+		if len(tt[i].r.Affix) != len(tt[j].r.Affix) {
+			return len(tt[i].r.Affix) > len(tt[j].r.Affix)
+		}
+		return tt[i].rt.Cutoff > tt[j].rt.Cutoff
+	})
+
+	for _, t := range tt {
+		for _, f := range formHypotheses(lemma, t.r, t.rt) {
+			if t.rt.ReBefore != nil && !t.rt.ReBefore.MatchString(f) {
+				continue
+			}
+			// The nouns in "o" take "es" lexically, e.g. "potatoes" comes from
+			// ExceptionsIdx, otherwise the plain "s" is preferred, e.g. "pianos"
+			if form == nlpgo.PosIdNns && f == lemma+"es" && strings.HasSuffix(lemma, "o") {
+				continue
+			}
+			cc := inflectCheck.LemmaCandidatesFor(f, []nlpgo.POSId{form}, 1)
+			if len(cc) == 0 {
+				continue
+			}
+			// The lemma with and without the final "e" share the form, e.g.
+			// "singing" is lemmatized to "singe" rather than "sing"
+			if cc[0].Val != lemma && (cc[0].Val != lemma+"e" || f != lemma+t.r.Affix) {
+				continue
+			}
+			if guessedMid(lemma, f, t.r, t.rt) && takenForm(lemma, f, form) {
+				continue
+			}
+			return f
+		}
+	}
+
+	return ""
+}
+
+// takenForm reports whether the form with the guessed chars belongs to
+// another lemma: ExceptionsIdx assigns it to the other lemmas only, e.g.
+// "pelves" is of "pelvis" rather than of "pelf", or it is a LemmaIdx lemma
+// and the affix, e.g. "graves" is of "grave" rather than of "graf".
+func takenForm(lemma, f string, form nlpgo.POSId) bool {
+	taken := false
+	for _, l := range ExceptionsIdx[f] {
+		if !hasPos(l.Pos, form) {
+			continue
+		}
+		if l.Val == lemma {
+			return false
+		}
+		taken = true
+	}
+	if taken {
+		return true
+	}
+
+	for i := range MorphRules {
+		r := &MorphRules[i]
+		if r.Kind == lm.AffixSuffix && hasPos(r.Pos, form) && strings.HasSuffix(f, r.Affix) &&
+			hasPosFor(f[:len(f)-len(r.Affix)], form) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// guessedMid reports whether the form has the chars between the lemma stem and
+// the affix which are not spelled by the rule, e.g. "ve" of "wolves" but not
+// the doubled "p" of "hopped", "i" of "tried", "e" of "churches" or "k" of
+// "mimicked"
+func guessedMid(lemma, f string, r *lm.Rule, rt *lm.RuleTransform) bool {
+	if rt.Subst != nil || rt.Cutoff <= utf8.RuneCountInString(r.Affix) {
+		return false
+	}
+	stem := lemma[:len(lemma)-len(rt.Augment)]
+	mid := f[len(stem) : len(f)-len(r.Affix)]
+
+	switch {
+	case mid == stem[len(stem)-1:]:
+	case rt.Augment == "y" && (mid == "i" || mid == "ie"):
+	case rt.Augment == "ie" && mid == "y":
+	case mid == "e" && strings.ContainsAny(stem[len(stem)-1:], "sxzho"):
+	case mid == "k" && strings.HasSuffix(stem, "c"):
+	default:
+		return true
+	}
+	return false
+}
+
+var (
+	// The vowel groups of a word, roughly its syllables
+	reSyllable = regexp.MustCompile(`[aeiouy]+`)
+	// The vowels of different syllables in a group, e.g. "bias"
+	reHiatus = regexp.MustCompile(`i[aou]|eo`)
+)
+
+// comparable reports whether the adjective takes "er", "est": a monosyllable
+// or a disyllable ending with "y", "le", "er" or "ow", e.g. "happier",
+// "simpler", otherwise the comparison is "more", "most", e.g. "more careful"
+func comparable(adj string) bool {
+	n := len(reSyllable.FindAllStringIndex(adj, -1)) + len(reHiatus.FindAllStringIndex(adj, -1))
+	// The silent final "e", e.g. "large"
+	if n > 1 && strings.HasSuffix(adj, "e") && !strings.ContainsAny(adj[len(adj)-2:len(adj)-1], "aeiouy") {
+		n--
+	}
+	if n <= 1 {
+		return true
+	}
+	if n > 2 {
+		return false
+	}
+	for _, end := range []string{"y", "le", "er", "ow"} {
+		if strings.HasSuffix(adj, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// A monosyllable ending with a single vowel and a consonant doubles it, e.g.
+// "stop" -> "stopped"
+var reDoubling = regexp.MustCompile(`^` + connosant + `*` + vowel + `[b-df-hj-np-tvz]$`)
+
+// formHypotheses returns the forms the transform may turn into the lemma.
+// The transform detaches Cutoff chars and augments Augment, so the form is the
+// lemma without Augment plus the Cutoff chars ending with the rule affix. The
+// chars before the affix (if any) are guessed.
+func formHypotheses(lemma string, r *lm.Rule, rt *lm.RuleTransform) []string {
+	if rt.Subst != nil {
+		return substHypotheses(lemma, rt)
+	}
+	if rt.HeadCutoff != 0 || rt.HeadAugment != "" || !strings.HasSuffix(lemma, rt.Augment) {
+		return nil
+	}
+
+	stem := lemma[:len(lemma)-len(rt.Augment)]
+	if stem == "" {
+		return nil
+	}
+	// The final "y" changes after a consonant only, e.g. "tried" but "played"
+	if rt.Augment == "y" && strings.ContainsAny(stem[len(stem)-1:], "aeiou") {
+		return nil
+	}
+	affixLen := utf8.RuneCountInString(r.Affix)
+
+	switch {
+	case rt.Cutoff < affixLen:
+		// The lemma keeps the affix head, e.g. "faked" -> "fake"
+		head := string([]rune(r.Affix)[:affixLen-rt.Cutoff])
+		if !strings.HasSuffix(stem, head) {
+			return nil
+		}
+		return []string{stem + r.Affix[len(head):]}
+	case rt.Cutoff == affixLen:
+		// The silent "e" is not dropped after a vowel, e.g. "seeing"
+		if rt.Augment == "e" && strings.ContainsAny(stem[len(stem)-1:], "aeiouy") {
+			return nil
+		}
+		// Nor after "ng" if the form is of another verb, e.g. "singeing"
+		// as "singing" is of "sing"
+		if rt.Augment == "e" && r.Affix == "ing" && strings.HasSuffix(stem, "ng") &&
+			hasPosFor(stem, nlpgo.PosIdVbg) {
+			return nil
+		}
+		// The final "e" merges with the affix one, e.g. "larger"
+		if strings.HasSuffix(stem, "e") && strings.HasPrefix(r.Affix, "e") {
+			return nil
+		}
+		return []string{stem + r.Affix}
+	}
+
+	// Guess the chars between the stem and the affix, e.g. "tried" -> "try"
+	var ff []string
+	last := stem[len(stem)-1:]
+	for _, mid := range midHypotheses(rt.Cutoff - affixLen) {
+		if mid == last && !reDoubling.MatchString(stem) {
+			continue
+		}
+		ff = append(ff, stem+mid+r.Affix)
+	}
+
+	return ff
+}
+
+// substHypotheses applies the substitution in reverse if it replaces a literal
+// ending with a literal, e.g. "clubfoot" -> "clubfeet"
+func substHypotheses(lemma string, rt *lm.RuleTransform) []string {
+	lit, _ := rt.Subst.LiteralPrefix()
+	if lit == "" || rt.Subst.String() != regexp.QuoteMeta(lit)+"$" ||
+		strings.Contains(rt.Replace, "$") || !strings.HasSuffix(lemma, rt.Replace) {
+		return nil
+	}
+
+	return []string{lemma[:len(lemma)-len(rt.Replace)] + lit}
+}
+
+// midHypotheses returns all the n letter strings (n is 1 or 2)
+func midHypotheses(n int) []string {
+	if n < 1 || n > 2 {
+		return nil
+	}
+
+	var ss []string
+	for c := 'a'; c <= 'z'; c++ {
+		ss = append(ss, string(c))
+	}
+	if n == 1 {
+		return ss
+	}
+
+	var ss2 []string
+	for _, a := range ss {
+		for _, b := range ss {
+			ss2 = append(ss2, a+b)
+		}
+	}
+
+	return ss2
+}
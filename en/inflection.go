@@ -36,10 +36,9 @@ var MorphRules = []lm.Rule{
 				MinValidLen: 6,
 			},
 			{
-				Cutoff:      4,
-				ReBefore:    regexp.MustCompile(`.ying$`),
-				Augment:     "ie",
-				MinValidLen: 5,
+				Cutoff:   4,
+				ReBefore: regexp.MustCompile(`.ying$`),
+				Augment:  "ie",
 			},
 			// fallback
 			{
@@ -201,4 +200,48 @@ var MorphRules = []lm.Rule{
 			},
 		},
 	},
+	// Internal stem changes of the irregular plurals, incl. compounds
+	{
+		Affix: "feet",
+		Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+		Transforms: []lm.RuleTransform{
+			// clubfeet -> clubfoot
+			{
+				Subst:   regexp.MustCompile(`feet$`),
+				Replace: "foot",
+			},
+		},
+	},
+	{
+		Affix: "teeth",
+		Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+		Transforms: []lm.RuleTransform{
+			// sawteeth -> sawtooth
+			{
+				Subst:   regexp.MustCompile(`teeth$`),
+				Replace: "tooth",
+			},
+		},
+	},
+	{
+		Affix: "geese",
+		Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+		Transforms: []lm.RuleTransform{
+			{
+				Subst:   regexp.MustCompile(`geese$`),
+				Replace: "goose",
+			},
+		},
+	},
+	{
+		Affix: "ice",
+		Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+		Transforms: []lm.RuleTransform{
+			// dormice -> dormouse, woodlice -> woodlouse
+			{
+				Subst:   regexp.MustCompile(`([lm])ice$`),
+				Replace: "${1}ouse",
+			},
+		},
+	},
 }
@@ -1,6 +1,7 @@
 package en
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,9 +62,8 @@ var testSet = [][]string{
 	{"potatoes", "potato"},
 	{"wolves", "wolf"},
 	{"women", "woman"},
-	// {"wiseish", "wise"},
-	// {"wolfish", "wolf"},
-	// {"jokeyish", "jockey"},
+	// The derivations, e.g. "wolfish" and "jokeyish", are covered by
+	// TestDerivRules
 }
 
 func TestMorphRules(t *testing.T) {
@@ -80,3 +80,255 @@ func TestMorphRules(t *testing.T) {
 		assert.Equal(v[1], ll.Val)
 	}
 }
+
+func TestMorphRulesAllMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	lmChecker := lm.NewLemmaIndex(LemmaIdx)
+
+	lzr := lm.NewLemmatizer(lmChecker, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lmChecker, lm.WithAllMatches()),
+	})
+	lemmata := func(word string) (vv []string) {
+		for _, l := range lzr.LemmaCandidates(word, 10) {
+			vv = append(vv, l.Val)
+		}
+		return
+	}
+
+	assert.Equal([]string{"ax", "axis", "axe"}, lemmata("axes"))
+	assert.Equal([]string{"saw", "see"}, lemmata("saw"))
+	assert.Equal([]string{"saw"}, lemmata("sawed"), "Expect rule and exception candidates merged")
+}
+
+func TestMorphRulesStemChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	lmChecker := lm.NewLemmaIndex(LemmaIdx)
+	lzr := lm.NewLemmatizer(lmChecker,
+		[]lm.LmResolver{lm.NewSuffixRuleResolver(MorphRules, lmChecker)})
+
+	// None of the words is in ExceptionsIdx
+	for _, v := range [][]string{
+		{"clubfeet", "clubfoot"},
+		{"crowfeet", "crowfoot"},
+		{"hindfeet", "hindfoot"},
+		{"sawteeth", "sawtooth"},
+		{"saberteeth", "sabertooth"},
+	} {
+		_, ok := ExceptionsIdx[v[0]]
+		assert.False(ok)
+		assert.Equal(lm.Lemma{Val: v[1], Pos: []nlpgo.POSId{nlpgo.PosIdNns}}, lzr.LemmatizeAs(v[0], nlpgo.PosIdNns))
+	}
+
+	assert.Equal([]lm.Lemma{{Val: "slice", Pos: LemmaIdx["slice"]}}, lzr.LemmaCandidates("slice", 10))
+}
+
+func TestLemmatizeAsExceptions(t *testing.T) {
+	assert := assert.New(t)
+
+	lmChecker := lm.NewLemmaIndex(LemmaIdx)
+	lzr := lm.NewLemmatizer(lmChecker, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lmChecker),
+	})
+
+	assert.Equal("leave", lzr.LemmatizeAs("leaves", nlpgo.PosIdVerb).Val)
+	assert.Equal(lm.Lemma{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}, lzr.LemmatizeAs("leaves", nlpgo.PosIdVbz))
+	assert.Equal("leave", lzr.LemmatizeAs("leaves", nlpgo.PosIdNoun).Val)
+	assert.Equal([]string{"leave", "leaf"}, func() (vv []string) {
+		for _, l := range lzr.LemmaCandidatesFor("leaves", []nlpgo.POSId{nlpgo.PosIdNns}, 5) {
+			vv = append(vv, l.Val)
+		}
+		return
+	}())
+}
+
+func TestMorphRulesFile(t *testing.T) {
+	assert := assert.New(t)
+
+	var b bytes.Buffer
+	assert.NoError(lm.WriteRules(&b, MorphRules))
+
+	rules, err := lm.LoadRules(&b)
+	assert.NoError(err)
+	assert.Equal(MorphRules, rules)
+}
+
+func TestMorphRulesLint(t *testing.T) {
+	assert := assert.New(t)
+
+	// The known order questions: the context-free transforms preceding the
+	// specific ones. A new issue must either be fixed or listed here.
+	type pos struct{ rule, transform int }
+	known := []pos{{0, 1}, {0, 2}, {0, 3}, {2, 3}, {3, 3}}
+
+	var got []pos
+	for _, v := range lm.LintRules(MorphRules) {
+		assert.Equal(lm.LintWarning, v.Severity, v.String())
+		got = append(got, pos{v.Rule, v.Transform})
+	}
+	assert.Equal(known, got)
+}
+
+func TestMorphRulesTrie(t *testing.T) {
+	assert := assert.New(t)
+
+	// The MorphRules affixes don't overlap, so the rule order is the same
+	lmChecker := lm.NewLemmaIndex(LemmaIdx)
+	linear := lm.NewLemmatizer(lmChecker,
+		[]lm.LmResolver{lm.NewSuffixRuleResolver(MorphRules, lmChecker, lm.WithAllMatches())})
+	trie := lm.NewLemmatizer(lmChecker,
+		[]lm.LmResolver{lm.NewSuffixTrieResolver(MorphRules, lmChecker, lm.WithAllMatches())})
+
+	words := append([]string{}, benchTokens...)
+	for _, v := range testSet {
+		words = append(words, v[0])
+	}
+	for _, w := range words {
+		assert.Equal(linear.Candidates(w, 10), trie.Candidates(w, 10), w)
+	}
+}
+
+// benchTokens is a mixed-text sample: the function words, the lemmata and the
+// inflected forms
+var benchTokens = []string{
+	"the", "children", "were", "playing", "in", "the", "gardens", "while",
+	"their", "parents", "walked", "slowly", "along", "the", "river", "and",
+	"talked", "about", "the", "hottest", "summer", "days", "they", "had",
+	"ever", "seen", "easier", "said", "than", "done", "she", "tried",
+	"running", "faster", "but", "the", "mice", "escaped", "into", "boxes",
+	"of", "potatoes", "countries", "churches", "wolves", "stopped", "hoping",
+	"for", "larger", "apples", "a", "to", "is", "it", "that", "classes",
+	"clubfeet", "women", "mimicked", "studies", "happier", "biggest", "zipped",
+}
+
+func BenchmarkMorphRules(b *testing.B) {
+	lmChecker := lm.NewLemmaIndex(LemmaIdx)
+	// Rejects every candidate, so all the transforms of the matched rules are
+	// tried and the accumulator stays empty
+	none := lm.LmCheckerFunc(func(string) lm.Lemma { return lm.Lemma{} })
+
+	for _, bm := range []struct {
+		name string
+		new  func([]lm.Rule, lm.LmChecker, ...lm.RuleOption) lm.LmResolver
+	}{
+		{"linear", lm.NewSuffixRuleResolver},
+		{"trie", lm.NewSuffixTrieResolver},
+	} {
+		// The rule matching alone
+		b.Run("match/"+bm.name, func(b *testing.B) {
+			rr := bm.new(MorphRules, none)
+			acc := lm.NewLemmaAccumulator()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rr.Resolve(benchTokens[i%len(benchTokens)], acc, 1)
+			}
+		})
+		// The whole pipeline without the input lookup
+		b.Run("lemmatize/"+bm.name, func(b *testing.B) {
+			lzr := lm.NewLemmatizer(none, []lm.LmResolver{bm.new(MorphRules, lmChecker)})
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lzr.Lemmatize(benchTokens[i%len(benchTokens)])
+			}
+		})
+	}
+}
+
+func TestInflect(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, v := range []struct {
+		lemma string
+		form  nlpgo.POSId
+		out   []string
+	}{
+		{"walk", nlpgo.PosIdVbd, []string{"walked"}},
+		{"walk", nlpgo.PosIdVbz, []string{"walks"}},
+		{"walk", nlpgo.PosIdVbp, []string{"walk"}},
+		{"hot", nlpgo.PosIdJjs, []string{"hottest"}},
+		{"mouse", nlpgo.PosIdNns, []string{"mice"}},
+		{"learn", nlpgo.PosIdVbd, []string{"learned", "learnt"}},
+		{"be", nlpgo.PosIdVbp, []string{"am", "are"}},
+		{"well", nlpgo.PosIdRbr, []string{"better"}},
+		{"hop", nlpgo.PosIdVbg, []string{"hopping"}},
+		{"hope", nlpgo.PosIdVbg, []string{"hoping"}},
+		{"see", nlpgo.PosIdVbg, []string{"seeing"}},
+		{"tie", nlpgo.PosIdVbg, []string{"tying"}},
+		{"be", nlpgo.PosIdVbg, []string{"being"}},
+		{"mimic", nlpgo.PosIdVbd, []string{"mimicked"}},
+		{"visit", nlpgo.PosIdVbd, []string{"visited"}},
+		{"try", nlpgo.PosIdVbd, []string{"tried"}},
+		{"play", nlpgo.PosIdVbd, []string{"played"}},
+		{"agree", nlpgo.PosIdVbd, []string{"agreed"}},
+		{"church", nlpgo.PosIdNns, []string{"churches"}},
+		{"country", nlpgo.PosIdNns, []string{"countries"}},
+		{"day", nlpgo.PosIdNns, []string{"days"}},
+		{"wolf", nlpgo.PosIdNns, []string{"wolves"}},
+		{"woman", nlpgo.PosIdNns, []string{"women"}},
+		{"large", nlpgo.PosIdJjr, []string{"larger"}},
+		{"small", nlpgo.PosIdJjs, []string{"smallest"}},
+		{"sad", nlpgo.PosIdJjr, []string{"sadder"}},
+		{"fly", nlpgo.PosIdVbz, []string{"flies"}},
+		{"sing", nlpgo.PosIdVbg, []string{"singing"}},
+		{"singe", nlpgo.PosIdVbg, []string{"singeing"}},
+		{"taxi", nlpgo.PosIdVbg, []string{"taxiing"}},
+		{"taxi", nlpgo.PosIdNns, []string{"taxis"}},
+		{"piano", nlpgo.PosIdNns, []string{"pianos"}},
+		{"potato", nlpgo.PosIdNns, []string{"potatoes"}},
+		{"clubfoot", nlpgo.PosIdNns, []string{"clubfeet"}},
+		{"pelf", nlpgo.PosIdNns, []string{"pelfs"}},
+		{"serf", nlpgo.PosIdNns, []string{"serfs"}},
+		{"happy", nlpgo.PosIdJjr, []string{"happier"}},
+		{"careful", nlpgo.PosIdJjr, nil},
+		{"bias", nlpgo.PosIdJjr, nil},
+		{"focus", nlpgo.PosIdNns, []string{"foci", "focuses"}},
+		{"index", nlpgo.PosIdNns, []string{"indexes", "indices"}},
+		{"formula", nlpgo.PosIdNns, []string{"formulae", "formulas"}},
+		{"fast", nlpgo.PosIdRbr, nil},
+		{"walk", nlpgo.PosIdJjr, nil},
+		{"xyzzy", nlpgo.PosIdNns, nil},
+	} {
+		assert.Equal(v.out, Inflect(v.lemma, v.form), "%s %s", v.lemma, v.form.POS())
+	}
+}
+
+func TestParadigm(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]Inflection{
+		{nlpgo.PosIdVbd, []string{"learned", "learnt"}},
+		{nlpgo.PosIdVbn, []string{"learned", "learnt"}},
+		{nlpgo.PosIdVbg, []string{"learning"}},
+		{nlpgo.PosIdVbp, []string{"learn"}},
+		{nlpgo.PosIdVbz, []string{"learns"}},
+	}, Paradigm("learn"))
+
+	assert.Equal([]Inflection{
+		{nlpgo.PosIdNns, []string{"goods"}},
+		{nlpgo.PosIdJjr, []string{"better"}},
+		{nlpgo.PosIdJjs, []string{"best"}},
+	}, Paradigm("good"), "No regular RBR, RBS forms of the adverb")
+
+	assert.Equal([]Inflection{
+		{nlpgo.PosIdVbd, []string{"was", "were"}},
+		{nlpgo.PosIdVbn, []string{"been"}},
+		{nlpgo.PosIdVbg, []string{"being"}},
+		{nlpgo.PosIdVbp, []string{"am", "are"}},
+		{nlpgo.PosIdVbz, []string{"is"}},
+		{nlpgo.PosIdNns, []string{"bes"}},
+	}, Paradigm("be"), "The rare noun is inflected too")
+
+	assert.Equal([]Inflection{
+		{nlpgo.PosIdNns, []string{"foci", "focuses"}},
+		{nlpgo.PosIdVbd, []string{"focused"}},
+		{nlpgo.PosIdVbn, []string{"focused"}},
+		{nlpgo.PosIdVbg, []string{"focusing"}},
+		{nlpgo.PosIdVbp, []string{"focus"}},
+		{nlpgo.PosIdVbz, []string{"focuses"}},
+	}, Paradigm("focus"))
+
+	assert.Nil(Paradigm("xyz"))
+}
@@ -0,0 +1,27 @@
+package en
+
+import "github.com/timurgarif/nlpgo"
+
+// Inflection is a cell of a paradigm table: the forms of a POS form
+type Inflection struct {
+	Pos   nlpgo.POSId
+	Forms []string
+}
+
+// Paradigm returns the inflected forms of the lemma for every POS LemmaIdx
+// lists for it, e.g. "learn" -> VBD: "learned", "learnt", VBN: "learned",
+// "learnt", VBG: "learning", VBP: "learn", VBZ: "learns". The forms are
+// generated by Inflect, the POS forms without any go omitted. Every POS is
+// inflected however rare it is, e.g. "be" as a noun -> NNS: "bes". The cells
+// are ordered by the LemmaIdx POS order and then by the POS form order.
+func Paradigm(lemma string) (pd []Inflection) {
+	for _, p := range LemmaIdx[lemma] {
+		for _, f := range p.Forms() {
+			if ff := Inflect(lemma, f); len(ff) > 0 {
+				pd = append(pd, Inflection{Pos: f, Forms: ff})
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,356 @@
+package en
+
+import "strings"
+
+// The words stemmed as a whole
+var stemExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// The words left as is after the plural removal
+var stemInvariants = map[string]bool{
+	"inning":  true,
+	"outing":  true,
+	"canning": true,
+	"herring": true,
+	"earring": true,
+	"proceed": true,
+	"exceed":  true,
+	"succeed": true,
+}
+
+// Stem returns the Porter2 (Snowball English) stem of the lower case word,
+// e.g. "generously" -> "generous", "knackeries" -> "knackeri". A stem is not
+// necessarily a word, use it with lm.NewStemResolver as an approximate base
+// form of the words the lemmatizer does not know.
+func Stem(word string) string {
+	if st, ok := stemExceptions[word]; ok {
+		return st
+	}
+	if len(word) < 3 {
+		return word
+	}
+
+	s := newStemmer(word)
+	s.step0()
+	s.step1a()
+	if stemInvariants[string(s.b)] {
+		return string(s.b)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+
+	return strings.Replace(string(s.b), "Y", "y", -1)
+}
+
+// stemmer is the word being stemmed with its R1 and R2 region offsets. The "y"
+// which is not a vowel is marked as "Y".
+type stemmer struct {
+	b      []byte
+	r1, r2 int
+}
+
+func newStemmer(word string) *stemmer {
+	s := &stemmer{b: []byte(strings.TrimPrefix(word, "'"))}
+
+	for i, c := range s.b {
+		if c == 'y' && (i == 0 || isStemVowel(s.b[i-1])) {
+			s.b[i] = 'Y'
+		}
+	}
+
+	s.r1 = s.region(0)
+	for _, p := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(s.b), p) {
+			s.r1 = len(p)
+			break
+		}
+	}
+	s.r2 = s.region(s.r1)
+
+	return s
+}
+
+func isStemVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// region returns the offset after the first non-vowel following a vowel
+// starting at i, the word length if none
+func (s *stemmer) region(i int) int {
+	for ; i < len(s.b) && !isStemVowel(s.b[i]); i++ {
+	}
+	for ; i < len(s.b) && isStemVowel(s.b[i]); i++ {
+	}
+	if i < len(s.b) {
+		return i + 1
+	}
+	return len(s.b)
+}
+
+// longest returns the longest suffix of the word among the ones given in the
+// length descending order, empty string if none
+func (s *stemmer) longest(sfxs ...string) string {
+	for _, sfx := range sfxs {
+		if s.hasSuffix(sfx) {
+			return sfx
+		}
+	}
+	return ""
+}
+
+func (s *stemmer) hasSuffix(sfx string) bool {
+	return strings.HasSuffix(string(s.b), sfx)
+}
+
+// in reports whether the suffix is in the region starting at r
+func (s *stemmer) in(sfx string, r int) bool {
+	return len(s.b)-len(sfx) >= r
+}
+
+// replace replaces the suffix of the word with rep
+func (s *stemmer) replace(sfx, rep string) {
+	s.b = append(s.b[:len(s.b)-len(sfx)], rep...)
+}
+
+// hasVowel reports whether the word has a vowel before the offset
+func (s *stemmer) hasVowel(end int) bool {
+	if end < 0 {
+		return false
+	}
+	for _, c := range s.b[:end] {
+		if isStemVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShort reports whether the word ends with a short syllable: a vowel
+// between two non-vowels (the last one is not "w", "x" or "Y"), or a vowel
+// followed by a non-vowel at the word start
+func (s *stemmer) endsShort() bool {
+	n := len(s.b)
+	if n < 2 || isStemVowel(s.b[n-1]) || !isStemVowel(s.b[n-2]) {
+		return false
+	}
+	if n == 2 {
+		return true
+	}
+	last := s.b[n-1]
+	return !isStemVowel(s.b[n-3]) && last != 'w' && last != 'x' && last != 'Y'
+}
+
+// step0 removes the possessive
+func (s *stemmer) step0() {
+	if sfx := s.longest("'s'", "'s", "'"); sfx != "" {
+		s.replace(sfx, "")
+	}
+}
+
+// step1a removes the plural
+func (s *stemmer) step1a() {
+	switch sfx := s.longest("sses", "ied", "ies", "us", "ss", "s"); sfx {
+	case "sses":
+		s.replace(sfx, "ss")
+	case "ied", "ies":
+		if len(s.b) > len(sfx)+1 {
+			s.replace(sfx, "i")
+		} else {
+			s.replace(sfx, "ie")
+		}
+	case "s":
+		// A vowel before the preceding letter, e.g. "gaps" but not "gas"
+		if s.hasVowel(len(s.b) - 2) {
+			s.replace(sfx, "")
+		}
+	}
+}
+
+// step1b removes the past tense and gerund endings
+func (s *stemmer) step1b() {
+	switch sfx := s.longest("eedly", "ingly", "edly", "eed", "ing", "ed"); sfx {
+	case "":
+	case "eed", "eedly":
+		if s.in(sfx, s.r1) {
+			s.replace(sfx, "ee")
+		}
+	default:
+		if !s.hasVowel(len(s.b) - len(sfx)) {
+			return
+		}
+		s.replace(sfx, "")
+
+		switch {
+		case s.longest("at", "bl", "iz") != "":
+			s.b = append(s.b, 'e')
+		case s.longest("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			s.b = s.b[:len(s.b)-1]
+		case s.r1 >= len(s.b) && s.endsShort():
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c turns the final "y" after a consonant (but the first letter) to "i"
+func (s *stemmer) step1c() {
+	n := len(s.b)
+	if n > 2 && (s.b[n-1] == 'y' || s.b[n-1] == 'Y') && !isStemVowel(s.b[n-2]) {
+		s.b[n-1] = 'i'
+	}
+}
+
+var step2Suffixes = []string{
+	"ization", "ational", "fulness", "ousness", "iveness",
+	"tional", "biliti", "lessli",
+	"entli", "ation", "alism", "aliti", "ousli", "iviti", "fulli",
+	"enci", "anci", "abli", "izer", "ator", "alli",
+	"bli", "ogi",
+	"li",
+}
+
+var step2Replaces = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"fulli":   "ful",
+	"lessli":  "less",
+}
+
+// step2 normalizes the derivational suffixes in R1
+func (s *stemmer) step2() {
+	sfx := s.longest(step2Suffixes...)
+	if sfx == "" || !s.in(sfx, s.r1) {
+		return
+	}
+
+	switch sfx {
+	case "ogi":
+		if s.hasSuffix("logi") {
+			s.replace(sfx, "og")
+		}
+	case "li":
+		// The valid li-endings
+		if n := len(s.b); n > 2 && strings.IndexByte("cdeghkmnrt", s.b[n-3]) >= 0 {
+			s.replace(sfx, "")
+		}
+	default:
+		s.replace(sfx, step2Replaces[sfx])
+	}
+}
+
+var step3Suffixes = []string{
+	"ational",
+	"tional",
+	"alize", "icate", "iciti", "ative",
+	"ical", "ness",
+	"ful",
+}
+
+var step3Replaces = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+}
+
+// step3 normalizes or removes the derivational suffixes in R1
+func (s *stemmer) step3() {
+	sfx := s.longest(step3Suffixes...)
+	if sfx == "" || !s.in(sfx, s.r1) {
+		return
+	}
+
+	if sfx == "ative" {
+		if s.in(sfx, s.r2) {
+			s.replace(sfx, "")
+		}
+		return
+	}
+	s.replace(sfx, step3Replaces[sfx])
+}
+
+var step4Suffixes = []string{
+	"ement",
+	"ance", "ence", "able", "ible", "ment",
+	"ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	"al", "er", "ic",
+}
+
+// step4 removes the derivational suffixes in R2
+func (s *stemmer) step4() {
+	sfx := s.longest(step4Suffixes...)
+	if sfx == "" || !s.in(sfx, s.r2) {
+		return
+	}
+
+	if sfx == "ion" && !s.hasSuffix("sion") && !s.hasSuffix("tion") {
+		return
+	}
+	s.replace(sfx, "")
+}
+
+// step5 removes the final "e" and the double "l"
+func (s *stemmer) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.in("e", s.r2) {
+			s.replace("e", "")
+		} else if s.in("e", s.r1) {
+			s.b = s.b[:len(s.b)-1]
+			if s.endsShort() {
+				s.b = append(s.b, 'e')
+			}
+		}
+	case s.hasSuffix("ll"):
+		if s.in("l", s.r2) {
+			s.replace("l", "")
+		}
+	}
+}
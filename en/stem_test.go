@@ -0,0 +1,99 @@
+package en
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+func TestStem(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, v := range []struct {
+		in, out string
+	}{
+		// Snowball English sample vocabulary
+		{"consign", "consign"},
+		{"consigned", "consign"},
+		{"consigning", "consign"},
+		{"consignment", "consign"},
+		{"consistency", "consist"},
+		{"consistently", "consist"},
+		{"consolation", "consol"},
+		{"consolatory", "consolatori"},
+		{"consoled", "consol"},
+		{"consolidate", "consolid"},
+		{"consolingly", "consol"},
+		{"conspicuously", "conspicu"},
+		{"conspiracy", "conspiraci"},
+		{"conspirators", "conspir"},
+		{"constable", "constabl"},
+		{"constancy", "constanc"},
+		{"knackeries", "knackeri"},
+		{"knaves", "knave"},
+		{"knavish", "knavish"},
+		{"kneaded", "knead"},
+		{"kneeled", "kneel"},
+		{"knees", "knee"},
+		{"knell", "knell"},
+		{"knightly", "knight"},
+		{"knitting", "knit"},
+		{"knives", "knive"},
+		{"knocker", "knocker"},
+		{"knopp", "knopp"},
+		// Regions and steps
+		{"generously", "generous"},
+		{"generate", "generat"},
+		{"communication", "communic"},
+		{"agreed", "agre"},
+		{"hopping", "hop"},
+		{"hoped", "hope"},
+		{"happily", "happili"},
+		{"cries", "cri"},
+		{"ties", "tie"},
+		{"gas", "gas"},
+		{"gaps", "gap"},
+		{"kiwis", "kiwi"},
+		{"cry", "cri"},
+		{"by", "by"},
+		{"say", "say"},
+		{"boy's", "boy"},
+		{"'tis", "tis"},
+		// Exceptions
+		{"dying", "die"},
+		{"news", "news"},
+		{"skies", "sky"},
+		{"proceeds", "proceed"},
+		{"innings", "inning"},
+	} {
+		assert.Equal(v.out, Stem(v.in), v.in)
+	}
+}
+
+func TestStemFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	lc := lm.NewLemmaIndex(LemmaIdx)
+	l := lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(ExceptionsIdx),
+		lm.NewSuffixRuleResolver(MorphRules, lc),
+		lm.NewStemResolver(Stem),
+	})
+
+	for _, v := range []struct {
+		in, out string
+		src     lm.Source
+	}{
+		{"walked", "walk", lm.SourceRule},
+		{"mice", "mouse", lm.SourceException},
+		{"blorfing", "blorf", lm.SourceStem},
+		{"glimmerations", "glimmer", lm.SourceStem},
+	} {
+		cc := l.Candidates(v.in, 5)
+		if assert.NotEmpty(cc, v.in) {
+			assert.Equal(v.out, cc[0].Val, v.in)
+			assert.Equal(v.src, cc[0].Source, v.in)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Package eval measures the lemmatization quality against gold data.
+*/
+package eval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/internal/tsv"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+// GoldEntry is a word form with its POS and the expected lemma
+type GoldEntry struct {
+	Form  string
+	Pos   nlpgo.POSId
+	Lemma string
+}
+
+// ReadGold reads the gold entries from r, one per line as tab separated form,
+// POS (a name like VBD or a numeric id) and lemma. Empty lines and the lines
+// starting with `#` are ignored.
+func ReadGold(r io.Reader) ([]GoldEntry, error) {
+	var gold []GoldEntry
+
+	err := tsv.ReadFields(r, []string{"form", "POS", "lemma"}, func(ff []string) error {
+		pos, err := nlpgo.ParsePOS(ff[1])
+		if err != nil {
+			return err
+		}
+		gold = append(gold, GoldEntry{Form: ff[0], Pos: pos, Lemma: ff[2]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gold, nil
+}
+
+type evaluator struct {
+	k         int
+	usePos    bool
+	maxErrors int
+}
+
+// Option defines a functional option type for Evaluate
+type Option func(*evaluator)
+
+// WithTopK sets the number of lemma candidates for the top-k accuracy, 5 by
+// default. Values < 1 are ignored.
+func WithTopK(k int) Option {
+	return func(e *evaluator) {
+		if k > 0 {
+			e.k = k
+		}
+	}
+}
+
+// WithPosConstraint makes the lemmatizer get the gold POS of every entry (see
+// lm.Lemmatizer.LemmaCandidatesFor). By default the POS is unknown to it.
+func WithPosConstraint() Option {
+	return func(e *evaluator) {
+		e.usePos = true
+	}
+}
+
+// WithMaxErrors sets the length of the most frequent errors list, 20 by
+// default. 0 disables the list, negative values are ignored.
+func WithMaxErrors(n int) Option {
+	return func(e *evaluator) {
+		if n >= 0 {
+			e.maxErrors = n
+		}
+	}
+}
+
+// Counts are the numbers of the gold entries evaluated
+type Counts struct {
+	Total int
+	// The first lemma candidate is the gold lemma
+	Correct int
+	// The gold lemma is among the top-k candidates
+	TopK int
+	// No lemma candidate at all
+	OOV int
+}
+
+// Accuracy returns the share of the entries lemmatized correctly
+func (c Counts) Accuracy() float64 {
+	return ratio(c.Correct, c.Total)
+}
+
+// TopKAccuracy returns the share of the entries with the gold lemma among the
+// top-k candidates
+func (c Counts) TopKAccuracy() float64 {
+	return ratio(c.TopK, c.Total)
+}
+
+// OOVRate returns the share of the entries without any lemma candidate
+func (c Counts) OOVRate() float64 {
+	return ratio(c.OOV, c.Total)
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func (c *Counts) add(correct, topK, oov bool) {
+	c.Total++
+	if correct {
+		c.Correct++
+	}
+	if topK {
+		c.TopK++
+	}
+	if oov {
+		c.OOV++
+	}
+}
+
+// PosCounts are the Counts of the entries of a gold POS
+type PosCounts struct {
+	Pos nlpgo.POSId
+	Counts
+}
+
+// LemmaError is a wrong first lemma candidate with its frequency. Got is
+// empty for the OOV words.
+type LemmaError struct {
+	Form  string
+	Pos   nlpgo.POSId
+	Gold  string
+	Got   string
+	Count int
+}
+
+// Report is the result of Evaluate
+type Report struct {
+	// The k of the top-k accuracy
+	K int
+	Counts
+	// The counts per gold POS in the POS id order
+	ByPos []PosCounts
+	// The most frequent errors, the most frequent first
+	Errors []LemmaError
+}
+
+// Evaluate lemmatizes the gold entry forms and compares the results to the
+// gold lemmata
+func Evaluate(lzr *lm.Lemmatizer, gold []GoldEntry, opts ...Option) *Report {
+	e := &evaluator{k: 5, maxErrors: 20}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	rep := &Report{K: e.k}
+	byPos := make(map[nlpgo.POSId]*PosCounts)
+	type errKey struct {
+		form, gold, got string
+		pos             nlpgo.POSId
+	}
+	errs := make(map[errKey]int)
+
+	for _, g := range gold {
+		var allowed []nlpgo.POSId
+		if e.usePos {
+			allowed = []nlpgo.POSId{g.Pos}
+		}
+		cc := lzr.LemmaCandidatesFor(g.Form, allowed, e.k)
+
+		var got string
+		if len(cc) > 0 {
+			got = cc[0].Val
+		}
+		topK := false
+		for _, c := range cc {
+			if c.Val == g.Lemma {
+				topK = true
+				break
+			}
+		}
+		correct := got == g.Lemma
+
+		rep.Counts.add(correct, topK, len(cc) == 0)
+		pc := byPos[g.Pos]
+		if pc == nil {
+			pc = &PosCounts{Pos: g.Pos}
+			byPos[g.Pos] = pc
+		}
+		pc.add(correct, topK, len(cc) == 0)
+
+		if !correct {
+			errs[errKey{g.Form, g.Lemma, got, g.Pos}]++
+		}
+	}
+
+	for _, pc := range byPos {
+		rep.ByPos = append(rep.ByPos, *pc)
+	}
+	sort.Slice(rep.ByPos, func(i, j int) bool {
+		return rep.ByPos[i].Pos < rep.ByPos[j].Pos
+	})
+
+	for k, n := range errs {
+		rep.Errors = append(rep.Errors, LemmaError{Form: k.form, Pos: k.pos, Gold: k.gold, Got: k.got, Count: n})
+	}
+	sort.Slice(rep.Errors, func(i, j int) bool {
+		a, b := rep.Errors[i], rep.Errors[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Form != b.Form {
+			return a.Form < b.Form
+		}
+		if a.Pos != b.Pos {
+			return a.Pos < b.Pos
+		}
+		return a.Got < b.Got
+	})
+	if len(rep.Errors) > e.maxErrors {
+		rep.Errors = rep.Errors[:e.maxErrors]
+	}
+
+	return rep
+}
+
+// WriteTo writes the report as text to w
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	buf := bufio.NewWriter(w)
+	bw := &countWriter{w: buf}
+
+	fmt.Fprintf(bw, "entries   %d\n", r.Total)
+	fmt.Fprintf(bw, "accuracy  %.4f\n", r.Accuracy())
+	fmt.Fprintf(bw, "top-%d     %.4f\n", r.K, r.TopKAccuracy())
+	fmt.Fprintf(bw, "oov       %.4f\n", r.OOVRate())
+
+	if len(r.ByPos) > 0 {
+		fmt.Fprintf(bw, "\n%-6s %8s %8s %8s %8s\n", "pos", "entries", "acc", "top-k", "oov")
+		for _, pc := range r.ByPos {
+			fmt.Fprintf(bw, "%-6s %8d %8.4f %8.4f %8.4f\n",
+				posName(pc.Pos), pc.Total, pc.Accuracy(), pc.TopKAccuracy(), pc.OOVRate())
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(bw, "\n%-6s %-6s %s\n", "count", "pos", "form: gold -> got")
+		for _, e := range r.Errors {
+			got := e.Got
+			if got == "" {
+				got = "(none)"
+			}
+			fmt.Fprintf(bw, "%-6d %-6s %s: %s -> %s\n", e.Count, posName(e.Pos), e.Form, e.Gold, got)
+		}
+	}
+
+	if err := buf.Flush(); err != nil {
+		return bw.n, err
+	}
+	return bw.n, bw.err
+}
+
+func posName(p nlpgo.POSId) string {
+	if name := p.POS(); name != "" {
+		return string(name)
+	}
+	return strconv.Itoa(int(p))
+}
+
+// countWriter counts the bytes written and keeps the first error
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
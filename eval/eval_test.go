@@ -0,0 +1,105 @@
+package eval
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/en"
+	"github.com/timurgarif/nlpgo/lm"
+)
+
+const testGold = `# form	POS	lemma
+walked	VBD	walk
+walked	VBD	walk
+saw	VBD	see
+saw	VBD	see
+mice	NNS	mouse
+xyzzies	NNS	xyzzy
+hottest	JJS	hot
+`
+
+func TestReadGold(t *testing.T) {
+	assert := assert.New(t)
+
+	gold, err := ReadGold(strings.NewReader(testGold))
+	assert.NoError(err)
+	assert.Len(gold, 7)
+	assert.Equal(GoldEntry{Form: "saw", Pos: nlpgo.PosIdVbd, Lemma: "see"}, gold[2])
+
+	gold, err = ReadGold(strings.NewReader("saw\t44\tsee"))
+	assert.NoError(err)
+	assert.Equal([]GoldEntry{{Form: "saw", Pos: nlpgo.PosIdVbd, Lemma: "see"}}, gold)
+
+	_, err = ReadGold(strings.NewReader("saw\tsee\n"))
+	assert.EqualError(err, "line 1: form, POS and lemma expected, got 2 fields")
+	_, err = ReadGold(strings.NewReader("saw\tVBX\tsee\n"))
+	assert.EqualError(err, `line 1: invalid POS "VBX"`)
+}
+
+func TestEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	gold, err := ReadGold(strings.NewReader(testGold))
+	assert.NoError(err)
+
+	lc := lm.NewLemmaIndex(en.LemmaIdx)
+	lzr := lm.NewLemmatizer(lc, []lm.LmResolver{
+		lm.NewExceptionResolver(en.ExceptionsIdx),
+		lm.NewSuffixRuleResolver(en.MorphRules, lc),
+	})
+
+	rep := Evaluate(lzr, gold, WithMaxErrors(2))
+	assert.Equal(5, rep.K)
+	assert.Equal(Counts{Total: 7, Correct: 4, TopK: 6, OOV: 1}, rep.Counts)
+	assert.InDelta(4.0/7, rep.Accuracy(), 1e-9)
+	assert.InDelta(6.0/7, rep.TopKAccuracy(), 1e-9)
+	assert.InDelta(1.0/7, rep.OOVRate(), 1e-9)
+	assert.Equal([]PosCounts{
+		{Pos: nlpgo.PosIdNns, Counts: Counts{Total: 2, Correct: 1, TopK: 1, OOV: 1}},
+		{Pos: nlpgo.PosIdJjs, Counts: Counts{Total: 1, Correct: 1, TopK: 1}},
+		{Pos: nlpgo.PosIdVbd, Counts: Counts{Total: 4, Correct: 2, TopK: 4}},
+	}, rep.ByPos)
+	assert.Equal([]LemmaError{
+		{Form: "saw", Pos: nlpgo.PosIdVbd, Gold: "see", Got: "saw", Count: 2},
+		{Form: "xyzzies", Pos: nlpgo.PosIdNns, Gold: "xyzzy", Count: 1},
+	}, rep.Errors)
+
+	// The verb "saw" is compatible with VBD as well
+	rep = Evaluate(lzr, gold, WithPosConstraint(), WithTopK(1))
+	assert.Equal(Counts{Total: 7, Correct: 4, TopK: 4, OOV: 1}, rep.Counts)
+	assert.Equal(1, rep.K)
+
+	lives := []GoldEntry{{Form: "lives", Pos: nlpgo.PosIdVbz, Lemma: "live"}}
+	assert.Equal(Counts{Total: 1, TopK: 1}, Evaluate(lzr, lives).Counts)
+	assert.Equal(Counts{Total: 1, Correct: 1, TopK: 1}, Evaluate(lzr, lives, WithPosConstraint()).Counts,
+		"Expect the gold POS to skip the noun life")
+
+	var b bytes.Buffer
+	n, err := Evaluate(lzr, gold[:3], WithTopK(2)).WriteTo(&b)
+	assert.NoError(err)
+	assert.Equal(int64(b.Len()), n)
+	assert.Equal(`entries   3
+accuracy  0.6667
+top-2     1.0000
+oov       0.0000
+
+pos     entries      acc    top-k      oov
+VBD           3   0.6667   1.0000   0.0000
+
+count  pos    form: gold -> got
+1      VBD    saw: see -> saw
+`, b.String())
+
+	// The invalid values are ignored
+	rep = Evaluate(lzr, gold, WithTopK(0), WithMaxErrors(-1))
+	assert.Equal(5, rep.K)
+	assert.Equal(6, rep.Counts.TopK)
+	assert.Len(rep.Errors, 2)
+	assert.Empty(Evaluate(lzr, gold, WithMaxErrors(0)).Errors)
+
+	assert.Equal(Counts{}, Evaluate(lzr, nil).Counts)
+	assert.Zero(Counts{}.Accuracy())
+}
@@ -0,0 +1,50 @@
+/*
+Package tsv reads the tab separated files of the lm and eval packages.
+*/
+package tsv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadFields reads the lines of tab separated fields from r and calls fn with
+// the trimmed fields of each line. Every line must have a field per name, the
+// names are used in the error messages, e.g. "form, lemma and POS expected".
+// Empty lines and the lines starting with `#` are ignored. The errors are
+// prefixed with the line number.
+func ReadFields(r io.Reader, names []string, fn func(ff []string) error) error {
+	scanner := bufio.NewScanner(r)
+	ln := 0
+	for scanner.Scan() {
+		ln++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		ff := strings.Split(line, "\t")
+		if len(ff) != len(names) {
+			return fmt.Errorf("line %d: %s expected, got %d fields", ln, joinNames(names), len(ff))
+		}
+		for i := range ff {
+			ff[i] = strings.TrimSpace(ff[i])
+		}
+		if err := fn(ff); err != nil {
+			return fmt.Errorf("line %d: %v", ln, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// joinNames returns e.g. "form, lemma and POS"
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,32 @@
+package tsv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFields(t *testing.T) {
+	assert := assert.New(t)
+
+	var rows [][]string
+	err := ReadFields(strings.NewReader("# comment\n a \t b\n\nc\td\n"), []string{"x", "y"}, func(ff []string) error {
+		rows = append(rows, ff)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal([][]string{{"a", "b"}, {"c", "d"}}, rows)
+
+	err = ReadFields(strings.NewReader("a\tb\nc\n"), []string{"x", "y", "z"}, func([]string) error { return nil })
+	assert.EqualError(err, "line 1: x, y and z expected, got 2 fields")
+
+	err = ReadFields(strings.NewReader("a\n\nb\n"), []string{"x"}, func(ff []string) error {
+		if ff[0] == "b" {
+			return errors.New("bad")
+		}
+		return nil
+	})
+	assert.EqualError(err, "line 3: bad")
+}
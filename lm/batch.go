@@ -0,0 +1,141 @@
+package lm
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// WithWorkers sets the number of goroutines used by the batch and stream
+// lemmatization. By default it's runtime.GOMAXPROCS(0). Values < 1 are
+// ignored.
+func WithWorkers(n int) LmOption {
+	return func(l *Lemmatizer) {
+		if n > 0 {
+			l.workers = n
+		}
+	}
+}
+
+func (l Lemmatizer) workerCount() int {
+	if l.workers > 0 {
+		return l.workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// LemmatizeBatch returns the first resolved lemma for each of the words,
+// in the input order. The words are lemmatized concurrently.
+func (l Lemmatizer) LemmatizeBatch(words []string) []Lemma {
+	ll, _ := l.LemmatizeBatchContext(context.Background(), words)
+	return ll
+}
+
+// LemmatizeBatchContext is like LemmatizeBatch but stops once ctx is done.
+// In that case ctx.Err() is returned along with the partial result: the words
+// not processed have the zero Lemma.
+func (l Lemmatizer) LemmatizeBatchContext(ctx context.Context, words []string) ([]Lemma, error) {
+	ll := make([]Lemma, len(words))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := l.workerCount(); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				ll[i] = l.Lemmatize(words[i])
+			}
+		}()
+	}
+
+	var err error
+loop:
+	for i := range words {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	return ll, err
+}
+
+// LemmatizeStream lemmatizes the words received from in concurrently and
+// sends the first resolved lemma of each word to the returned channel, in the
+// input order. The returned channel is closed once in is closed and all its
+// words are processed, or once ctx is done.
+func (l Lemmatizer) LemmatizeStream(ctx context.Context, in <-chan string) <-chan Lemma {
+	type job struct {
+		word string
+		res  chan Lemma
+	}
+
+	workers := l.workerCount()
+	jobs := make(chan job)
+	// Result futures in the input order
+	pending := make(chan chan Lemma, workers)
+	out := make(chan Lemma)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for j := range jobs {
+				j.res <- l.Lemmatize(j.word)
+			}
+		}()
+	}
+
+	// Dispatch the words to the workers keeping the order of the futures
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for {
+			var word string
+			var ok bool
+			select {
+			case word, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			j := job{word: word, res: make(chan Lemma, 1)}
+			select {
+			case pending <- j.res:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Collect the results in the input order
+	go func() {
+		defer close(out)
+		for res := range pending {
+			var lm Lemma
+			select {
+			case lm = <-res:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case out <- lm:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
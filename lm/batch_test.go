@@ -0,0 +1,91 @@
+package lm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func getBatchLemmatizer(opts ...LmOption) *Lemmatizer {
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"walk": {nlpgo.PosIdVerb},
+		"talk": {nlpgo.PosIdVerb},
+	})
+	rules := []Rule{
+		{
+			Affix:      "ed",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbd},
+			Transforms: []RuleTransform{{Cutoff: 2}},
+		},
+	}
+
+	return NewLemmatizer(lmIdx, []LmResolver{NewSuffixRuleResolver(rules, lmIdx)}, opts...)
+}
+
+func getBatchWords(n int) (words []string, expected []Lemma) {
+	for i := 0; i < n; i++ {
+		switch i % 3 {
+		case 0:
+			words = append(words, "walked")
+			expected = append(expected, Lemma{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbd}})
+		case 1:
+			words = append(words, "talk")
+			expected = append(expected, Lemma{Val: "talk", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}})
+		default:
+			words = append(words, fmt.Sprintf("unknown%d", i))
+			expected = append(expected, Lemma{})
+		}
+	}
+	return
+}
+
+func TestLemmatizeBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	words, expected := getBatchWords(1000)
+	for _, w := range []int{1, 3, 16} {
+		assert.Equal(expected, getBatchLemmatizer(WithWorkers(w)).LemmatizeBatch(words))
+	}
+	assert.Equal(expected, getBatchLemmatizer().LemmatizeBatch(words))
+	assert.Empty(getBatchLemmatizer().LemmatizeBatch(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ll, err := getBatchLemmatizer().LemmatizeBatchContext(ctx, words)
+	assert.Equal(context.Canceled, err)
+	assert.Len(ll, len(words))
+}
+
+func TestLemmatizeStream(t *testing.T) {
+	assert := assert.New(t)
+
+	words, expected := getBatchWords(1000)
+	for _, w := range []int{1, 3, 16} {
+		in := make(chan string)
+		go func() {
+			for _, w := range words {
+				in <- w
+			}
+			close(in)
+		}()
+
+		var actual []Lemma
+		for lm := range getBatchLemmatizer(WithWorkers(w)).LemmatizeStream(context.Background(), in) {
+			actual = append(actual, lm)
+		}
+		assert.Equal(expected, actual)
+	}
+
+	// The output is closed on cancellation even if the input is not
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	out := getBatchLemmatizer().LemmatizeStream(ctx, in)
+	in <- "walked"
+	assert.Equal(expected[0], <-out)
+	cancel()
+	for range out {
+	}
+}
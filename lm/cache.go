@@ -0,0 +1,146 @@
+package lm
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/timurgarif/nlpgo"
+)
+
+// CacheStats reports the result cache usage of a Lemmatizer
+type CacheStats struct {
+	// Number of cached results and the cache capacity
+	Len, Size int
+	Hits      uint64
+	Misses    uint64
+	// Number of results evicted as least recently used
+	Evictions uint64
+}
+
+// resultCache is a bounded concurrency-safe LRU cache of lemmatization
+// results.
+type resultCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	m     map[string]*list.Element
+	stats CacheStats
+}
+
+type cacheEntry struct {
+	key string
+	cc  []Candidate
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size: size,
+		ll:   list.New(),
+		m:    make(map[string]*list.Element, size),
+	}
+}
+
+func cacheKey(word string, allowed []nlpgo.POSId, max int) string {
+	var b strings.Builder
+	b.WriteString(word)
+	b.WriteByte(0)
+	for _, p := range allowed {
+		b.WriteByte(byte(p))
+	}
+	b.WriteByte(0)
+	b.WriteString(strconv.Itoa(max))
+
+	return b.String()
+}
+
+func (c *resultCache) get(key string) ([]Candidate, bool) {
+	c.mu.Lock()
+	e, ok := c.m[key]
+	if !ok {
+		c.stats.Misses++
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.stats.Hits++
+	c.ll.MoveToFront(e)
+	cc := e.Value.(*cacheEntry).cc
+	c.mu.Unlock()
+
+	// The cached value is never modified, so it's safe to copy it unlocked
+	return copyCandidates(cc), true
+}
+
+func (c *resultCache) put(key string, cc []Candidate) {
+	cc = copyCandidates(cc)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.m[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*cacheEntry).cc = cc
+		return
+	}
+
+	c.m[key] = c.ll.PushFront(&cacheEntry{key: key, cc: cc})
+	if c.ll.Len() > c.size {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.m, last.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+func (c *resultCache) purge() {
+	c.mu.Lock()
+	c.ll.Init()
+	c.m = make(map[string]*list.Element, c.size)
+	c.mu.Unlock()
+}
+
+func (c *resultCache) getStats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Len = c.ll.Len()
+	s.Size = c.size
+
+	return s
+}
+
+// CacheStats returns the result cache statistics. It's the zero value if the
+// cache is disabled (see WithCache).
+func (l Lemmatizer) CacheStats() CacheStats {
+	if l.cache == nil {
+		return CacheStats{}
+	}
+	return l.cache.getStats()
+}
+
+// PurgeCache drops all the cached results, the statistics counters are kept.
+// Call it once the lemma index, exceptions or rules used by the Lemmatizer
+// change.
+func (l Lemmatizer) PurgeCache() {
+	if l.cache != nil {
+		l.cache.purge()
+	}
+}
+
+// copyCandidates makes a deep copy so that callers can't modify the cached
+// values
+func copyCandidates(cc []Candidate) []Candidate {
+	if cc == nil {
+		return nil
+	}
+
+	cp := make([]Candidate, len(cc))
+	copy(cp, cc)
+	for i := range cp {
+		cp[i].Pos = append([]nlpgo.POSId(nil), cp[i].Pos...)
+	}
+
+	return cp
+}
@@ -0,0 +1,91 @@
+package lm
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestResultCache(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newResultCache(2)
+	a := []Candidate{{Lemma: Lemma{Val: "a", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}}}}
+	b := []Candidate{{Lemma: Lemma{Val: "b"}}}
+
+	_, ok := c.get("a")
+	assert.False(ok)
+	c.put("a", a)
+	c.put("b", b)
+	cc, ok := c.get("a")
+	assert.True(ok)
+	assert.Equal(a, cc)
+
+	// "b" is the least recently used
+	c.put("c", nil)
+	_, ok = c.get("b")
+	assert.False(ok)
+	_, ok = c.get("a")
+	assert.True(ok)
+	cc, ok = c.get("c")
+	assert.True(ok)
+	assert.Nil(cc, "Expect empty results cached too")
+
+	c.put("a", b)
+	cc, _ = c.get("a")
+	assert.Equal(b, cc, "Expect value replaced")
+
+	assert.Equal(CacheStats{Len: 2, Size: 2, Hits: 4, Misses: 2, Evictions: 1}, c.getStats())
+
+	c.purge()
+	_, ok = c.get("a")
+	assert.False(ok)
+	assert.Equal(CacheStats{Len: 0, Size: 2, Hits: 4, Misses: 3, Evictions: 1}, c.getStats())
+}
+
+func TestLemmatizerCache(t *testing.T) {
+	assert := assert.New(t)
+
+	idx := map[string][]nlpgo.POSId{"walk": {nlpgo.PosIdVerb}}
+	l := NewLemmatizer(NewLemmaIndex(idx), nil, WithCache(100))
+
+	assert.Equal("walk", l.Lemmatize("walk").Val)
+	assert.Equal("walk", l.Lemmatize("walk").Val)
+	assert.Equal(CacheStats{Len: 1, Size: 100, Hits: 1, Misses: 1}, l.CacheStats())
+
+	// Dictionary change is visible after purge only
+	delete(idx, "walk")
+	assert.Equal("walk", l.Lemmatize("walk").Val)
+	l.PurgeCache()
+	assert.Equal("", l.Lemmatize("walk").Val)
+
+	l = NewLemmatizer(NewLemmaIndex(idx), nil)
+	l.Lemmatize("walk")
+	l.PurgeCache()
+	assert.Equal(CacheStats{}, l.CacheStats(), "Expect no stats without cache")
+}
+
+func TestLemmatizerCacheConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	words, expected := getBatchWords(300)
+	l := getBatchLemmatizer(WithCache(50))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, w := range words {
+				assert.Equal(expected[i], l.Lemmatize(w))
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := l.CacheStats()
+	assert.Equal(uint64(8*300), stats.Hits+stats.Misses)
+	assert.True(stats.Len <= 50)
+}
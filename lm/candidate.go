@@ -0,0 +1,84 @@
+package lm
+
+// Source identifies the lemmatization step that produced a Candidate
+type Source uint8
+
+const (
+	// The candidate was added by a custom resolver with plain Set
+	SourceUnknown Source = iota
+	// The input word is a lemma in the LmChecker
+	SourceLemma
+	// The candidate comes from an exception index entry
+	SourceException
+	// The candidate is produced by a Rule and confirmed by the LmChecker
+	SourceRule
+	// The candidate is a stem of the word, not a dictionary lemma
+	SourceStem
+	// The candidate is produced by a Rule without the LmChecker
+	SourceGuess
+	// The candidate is the base lemma of a derived word (see DerivRule)
+	SourceDerivation
+	// The candidate is the lemma of the host word of a token with clitics,
+	// e.g. "do" for "don't"
+	SourceClitic
+)
+
+var sourceNames = [...]string{
+	SourceUnknown:    "unknown",
+	SourceLemma:      "lemma",
+	SourceException:  "exception",
+	SourceRule:       "rule",
+	SourceStem:       "stem",
+	SourceGuess:      "guess",
+	SourceDerivation: "derivation",
+	SourceClitic:     "clitic",
+}
+
+func (s Source) String() string {
+	if int(s) < len(sourceNames) {
+		return sourceNames[s]
+	}
+	return "unknown"
+}
+
+// Default candidate scores per source. A dictionary hit scores higher than
+// a rule-based one, a rule transform with a context regexp scores higher than
+// a context-free fallback. The host word lemma of a token with clitics is as
+// reliable as a rule one. A derivation base is less reliable as the affix
+// meaning is not always compositional. A guess is scaled by its plausibility,
+// a stem is the least reliable one.
+const (
+	ScoreLemma       = 1.0
+	ScoreException   = 0.9
+	ScoreRuleContext = 0.8
+	ScoreRule        = 0.7
+	ScoreClitic      = ScoreRule
+	ScoreDerivation  = 0.6
+	ScoreUnknown     = 0.5
+	ScoreGuess       = 0.4
+	ScoreStem        = 0.3
+)
+
+// Candidate is a lemma candidate with its provenance
+type Candidate struct {
+	Lemma
+	// The input word as passed in, before normalization
+	Surface string
+	// The step which produced the candidate
+	Source Source
+	// Index of the resolver in the Lemmatizer resolver list, -1 if the
+	// candidate is the input word itself.
+	Resolver int
+	// Affix of the matched Rule (SourceRule, SourceGuess and SourceDerivation
+	// only)
+	Affix string
+	// Index of the matched RuleTransform within Rule.Transforms, -1 if the
+	// candidate is not produced by a rule.
+	Transform int
+	// The derivation type of the matched DerivRule (SourceDerivation only)
+	Derivation string
+	// Whether the input word is a lemma in the LmChecker
+	InputIsLemma bool
+	// Confidence score in the [0, 1] range
+	Score float64
+}
@@ -0,0 +1,111 @@
+package lm
+
+import "github.com/timurgarif/nlpgo"
+
+// DerivRule is a Rule linking a derived word to its base lemma, e.g.
+// "kindness" -> "kind". The Rule Pos are the POS'es the base lemma must have
+// (e.g. nlpgo.PosIdAdj for "-ness"), not the word forms.
+type DerivRule struct {
+	Rule
+	// The derivation type, e.g. "ness"
+	Type string
+	// The POS of the derived word, e.g. nlpgo.PosIdNoun for "-ness"
+	DerivedPos nlpgo.POSId
+}
+
+type derivResolver struct {
+	rs []DerivRule
+	lc LmChecker
+}
+
+// NewDerivationResolver creates a resolver which adds the base lemmata of the
+// derived words, e.g. "wolfish" -> "wolf". It's not a part of the
+// inflectional lemmatization, so it's to be added explicitly after the
+// inflectional resolvers.
+//
+// The rules of any AffixKind are applied in the given order, the first
+// transform confirmed by the LmChecker wins for each rule. The base lemma
+// is added with SourceDerivation, the derivation type and the derived word
+// POS (so the POS constraint applies to the derived word). The base lemmata
+// can't be confirmed without the LmChecker, so a nil lc yields no candidates.
+func NewDerivationResolver(rules []DerivRule, lc LmChecker) LmResolver {
+	return derivResolver{rs: rules, lc: lc}
+}
+
+func (dr derivResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
+	// No base lemma is valid without the lemma checker
+	if dr.lc == nil {
+		return
+	}
+
+	wdRuneLen := len([]rune(word))
+
+	for i := range dr.rs {
+		r := &dr.rs[i]
+		at := r.match(word)
+		if at < 0 {
+			continue
+		}
+
+		pp, ok := acc.FilterPos([]nlpgo.POSId{r.DerivedPos})
+		if acc.Tracing() {
+			st := TraceStep{Kind: StepRule, Word: word, Affix: r.Affix, Transform: -1, Pos: []nlpgo.POSId{r.DerivedPos}}
+			if !ok {
+				st.Reject = RejectPos
+			}
+			acc.Trace(st)
+		}
+		if !ok {
+			continue
+		}
+
+		for ti := range r.Transforms {
+			c, rej := r.Transforms[ti].transform(word, wdRuneLen, &r.Rule, at, nil)
+
+			// The base lemma must have one of the rule POS'es
+			var l Lemma
+			if rej == RejectNone {
+				if l = dr.lc.Lookup(c); l.Val == "" {
+					rej = RejectLookup
+				} else if !hasAnyPos(l.Pos, r.Pos) {
+					rej = RejectPos
+				}
+			}
+
+			if acc.Tracing() {
+				st := TraceStep{Kind: StepTransform, Word: word, Affix: r.Affix, Transform: ti, Candidate: c, Reject: rej}
+				if rej == RejectNone {
+					st.Pos = pp
+				} else if rej == RejectPos {
+					st.Pos = l.Pos
+				}
+				acc.Trace(st)
+			}
+			if rej != RejectNone {
+				continue
+			}
+
+			acc.Add(Candidate{
+				Lemma:      Lemma{Val: l.Val, Pos: pp},
+				Source:     SourceDerivation,
+				Affix:      r.Affix,
+				Transform:  ti,
+				Derivation: r.Type,
+				Score:      ScoreDerivation,
+			})
+			if acc.Len() >= max {
+				return
+			}
+			break
+		}
+	}
+}
+
+func hasAnyPos(pp, of []nlpgo.POSId) bool {
+	for _, p := range of {
+		if hasPos(pp, p) {
+			return true
+		}
+	}
+	return false
+}
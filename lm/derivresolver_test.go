@@ -0,0 +1,67 @@
+package lm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestDerivationResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"kind":     {nlpgo.PosIdAdj, nlpgo.PosIdNoun},
+		"kindness": {nlpgo.PosIdNoun},
+		"fish":     {nlpgo.PosIdNoun, nlpgo.PosIdVerb},
+	})
+	rules := []DerivRule{
+		{
+			Rule:       Rule{Affix: "ness", Pos: []nlpgo.POSId{nlpgo.PosIdAdj}, Transforms: []RuleTransform{{Cutoff: 4}}},
+			Type:       "ness",
+			DerivedPos: nlpgo.PosIdNoun,
+		},
+		{
+			Rule:       Rule{Affix: "er", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}, Transforms: []RuleTransform{{Cutoff: 2}}},
+			Type:       "agent",
+			DerivedPos: nlpgo.PosIdNoun,
+		},
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{NewDerivationResolver(rules, lmIdx)})
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:        Lemma{Val: "kindness", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+			Surface:      "kindness",
+			Source:       SourceLemma,
+			Resolver:     -1,
+			Transform:    -1,
+			InputIsLemma: true,
+			Score:        ScoreLemma,
+		},
+		{
+			Lemma:        Lemma{Val: "kind", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+			Surface:      "kindness",
+			Source:       SourceDerivation,
+			Affix:        "ness",
+			Transform:    0,
+			Derivation:   "ness",
+			InputIsLemma: true,
+			Score:        ScoreDerivation,
+		},
+	}, l.Candidates("kindness", 5))
+
+	assert.Equal([]Lemma{{Val: "fish", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}}}, l.LemmaCandidates("fisher", 5))
+	assert.Nil(l.LemmaCandidatesFor("fisher", []nlpgo.POSId{nlpgo.PosIdVerb}, 5), "The derived word is a noun")
+	assert.Nil(l.LemmaCandidates("kinder", 5), "The base lemma is not a verb")
+	assert.Equal("derivation", SourceDerivation.String())
+
+	_, steps := l.Explain("kinder", 5)
+	assert.Contains(steps, TraceStep{Kind: StepTransform, Word: "kinder", Affix: "er", Transform: 0, Candidate: "kind",
+		Pos: []nlpgo.POSId{nlpgo.PosIdAdj, nlpgo.PosIdNoun}, Reject: RejectPos})
+
+	l = NewLemmatizer(lmIdx, []LmResolver{NewDerivationResolver(rules, nil)})
+	assert.Equal([]Lemma{{Val: "kindness", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}}}, l.LemmaCandidates("kindness", 5),
+		"No base lemma without the lemma checker")
+	assert.Nil(l.LemmaCandidates("fisher", 5), "No base lemma without the lemma checker")
+}
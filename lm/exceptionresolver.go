@@ -8,11 +8,42 @@ func NewExceptionResolver(excidx map[string][]Lemma) LmResolver {
 	return exceptResolver{excidx: excidx}
 }
 
-func (r exceptResolver) Resolve(word string, acc LemmaAccumulator, max int) {
-	if lemmata, ok := r.excidx[word]; ok {
+// Resolve adds the lemmata of the exception entry for the word. The lemmata of
+// one entry are alternatives of the same rank.
+func (r exceptResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
+	lemmata, ok := r.excidx[word]
+	if !ok && acc.Tracing() {
+		acc.Trace(TraceStep{Kind: StepException, Word: word, Transform: -1, Reject: RejectLookup})
+	}
+
+	if ok {
+		first := true
 		for _, lm := range lemmata {
-			acc.Set(lm.Val, lm.Pos)
-			if len(acc) >= max {
+			pp, ok := acc.FilterPos(lm.Pos)
+			if acc.Tracing() {
+				st := TraceStep{Kind: StepException, Word: word, Transform: -1, Candidate: lm.Val, Pos: pp}
+				if !ok {
+					st.Pos, st.Reject = lm.Pos, RejectPos
+				}
+				acc.Trace(st)
+			}
+			if !ok {
+				continue
+			}
+
+			c := Candidate{
+				Lemma:     Lemma{Val: lm.Val, Pos: pp},
+				Source:    SourceException,
+				Transform: -1,
+				Score:     ScoreException,
+			}
+			if first {
+				acc.Add(c)
+				first = false
+			} else {
+				acc.AddTied(c)
+			}
+			if acc.Len() >= max {
 				return
 			}
 		}
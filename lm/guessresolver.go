@@ -0,0 +1,94 @@
+package lm
+
+import "sort"
+
+// Plausibility returns how plausible the lemma candidate looks in the [0, 1]
+// range, 0 if it must be dropped
+type Plausibility func(lemma string) float64
+
+type guessResolver struct {
+	rs        []Rule
+	plausible Plausibility
+}
+
+// NewGuessResolver creates a fallback resolver which applies the suffix rules
+// without an LmChecker if no preceding resolver (nor the input lookup) has
+// added a candidate, e.g. for the neologisms and brand names like "zoomed".
+// Every transform of every matched rule yields a guess, the guesses are ranked
+// by their plausibility and added with SourceGuess and ScoreGuess scaled by
+// it. The implausible ones are dropped.
+func NewGuessResolver(rules []Rule, plausible Plausibility) LmResolver {
+	return guessResolver{rs: rules, plausible: plausible}
+}
+
+// Resolve adds the plausible guesses if acc is empty
+func (r guessResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
+	if acc.Len() > 0 {
+		return
+	}
+
+	wdRuneLen := len([]rune(word))
+	var cc []Candidate
+	for i := range r.rs {
+		rl := &r.rs[i]
+		if rl.Kind != AffixSuffix {
+			continue
+		}
+		at := rl.match(word)
+		if at < 0 {
+			continue
+		}
+
+		rpp, ok := acc.FilterPos(rl.Pos)
+		if acc.Tracing() {
+			st := TraceStep{Kind: StepRule, Word: word, Affix: rl.Affix, Transform: -1, Pos: rl.Pos}
+			if !ok {
+				st.Reject = RejectPos
+			}
+			acc.Trace(st)
+		}
+		if !ok {
+			continue
+		}
+
+		for ti := range rl.Transforms {
+			c, rej := rl.Transforms[ti].transform(word, wdRuneLen, rl, at, nil)
+			var p float64
+			if rej == RejectNone {
+				if p = r.plausible(c); p <= 0 {
+					rej = RejectImplausible
+				}
+			}
+
+			if acc.Tracing() {
+				st := TraceStep{Kind: StepTransform, Word: word, Affix: rl.Affix, Transform: ti, Candidate: c, Reject: rej}
+				if rej == RejectNone {
+					st.Pos = rpp
+				}
+				acc.Trace(st)
+			}
+			if rej != RejectNone {
+				continue
+			}
+
+			cc = append(cc, Candidate{
+				Lemma:     Lemma{Val: c, Pos: rpp},
+				Source:    SourceGuess,
+				Affix:     rl.Affix,
+				Transform: ti,
+				Score:     ScoreGuess * p,
+			})
+		}
+	}
+
+	// The more plausible first, the rule order otherwise
+	sort.SliceStable(cc, func(i, j int) bool {
+		return cc[i].Score > cc[j].Score
+	})
+	for _, c := range cc {
+		acc.Add(c)
+		if acc.Len() >= max {
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package lm
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestGuessResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"walk": {nlpgo.PosIdVerb},
+	})
+	rules := []Rule{
+		{
+			Affix: "ed",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbd},
+			Transforms: []RuleTransform{
+				{Cutoff: 1},
+				{Cutoff: 3, ReBefore: regexp.MustCompile(`(pp|tt)ed$`)},
+				{Cutoff: 2},
+			},
+		},
+	}
+	// Penalize the final "e" and the double consonant
+	plausible := func(lemma string) float64 {
+		switch {
+		case len(lemma) < 3:
+			return 0
+		case strings.HasSuffix(lemma, "e"):
+			return 0.5
+		case strings.HasSuffix(lemma, "pp"):
+			return 0.25
+		}
+		return 1
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{
+		NewSuffixRuleResolver(rules, lmIdx),
+		NewGuessResolver(rules, plausible),
+	})
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbd}},
+			Surface:   "walked",
+			Source:    SourceRule,
+			Affix:     "ed",
+			Transform: 2,
+			Score:     ScoreRule,
+		},
+	}, l.Candidates("walked", 5), "No guess if a lemma is found")
+
+	cc := l.Candidates("zipped", 5)
+	assert.Equal([]string{"zip", "zippe", "zipp"}, []string{cc[0].Val, cc[1].Val, cc[2].Val})
+	assert.Equal(Candidate{
+		Lemma:     Lemma{Val: "zip", Pos: []nlpgo.POSId{nlpgo.PosIdVbd}},
+		Surface:   "zipped",
+		Source:    SourceGuess,
+		Resolver:  1,
+		Affix:     "ed",
+		Transform: 1,
+		Score:     ScoreGuess,
+	}, cc[0])
+	assert.Equal(ScoreGuess*0.5, cc[1].Score)
+	assert.Len(l.Candidates("zipped", 2), 2)
+
+	assert.Nil(l.LemmaCandidatesFor("zipped", []nlpgo.POSId{nlpgo.PosIdNoun}, 5))
+	assert.Nil(l.Candidates("red", 5), "Implausible guesses are dropped")
+	assert.Equal("guess", SourceGuess.String())
+
+	_, steps := l.Explain("red", 5)
+	assert.Contains(steps, TraceStep{Kind: StepTransform, Resolver: 1, Word: "red", Affix: "ed", Transform: 2, Candidate: "r", Reject: RejectImplausible})
+}
@@ -0,0 +1,333 @@
+package lm
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/timurgarif/nlpgo"
+	"github.com/timurgarif/nlpgo/internal/tsv"
+)
+
+// WordPair is an inflected word form with its lemma and the form POS, e.g.
+// "walked", "walk", nlpgo.PosIdVbd
+type WordPair struct {
+	Form  string
+	Lemma string
+	Pos   nlpgo.POSId
+}
+
+// ReadWordPairs reads the word pairs from r, one per line as tab separated
+// form, lemma and POS (a name like VBD or a numeric id). Empty lines and the
+// lines starting with `#` are ignored.
+func ReadWordPairs(r io.Reader) ([]WordPair, error) {
+	var pairs []WordPair
+
+	err := tsv.ReadFields(r, []string{"form", "lemma", "POS"}, func(ff []string) error {
+		pos, err := nlpgo.ParsePOS(ff[2])
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, WordPair{Form: ff[0], Lemma: ff[1], Pos: pos})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+type inducer struct {
+	minSupport   int
+	minPrecision float64
+	maxContext   int
+}
+
+// InduceOption defines a functional option type for InduceRules
+type InduceOption func(*inducer)
+
+// WithMinSupport sets the minimal number of pairs a transform must explain,
+// 2 by default.
+func WithMinSupport(n int) InduceOption {
+	return func(in *inducer) {
+		in.minSupport = n
+	}
+}
+
+// WithMinPrecision sets the share of the matched training forms a transform
+// must lemmatize correctly to go without a longer context, 0.9 by default.
+func WithMinPrecision(p float64) InduceOption {
+	return func(in *inducer) {
+		in.minPrecision = p
+	}
+}
+
+// WithMaxContext sets the maximal number of chars before the affix used in the
+// ReBefore contexts, 3 by default.
+func WithMaxContext(n int) InduceOption {
+	return func(in *inducer) {
+		in.maxContext = n
+	}
+}
+
+// change is the ending replacement turning the form into the lemma
+type change struct {
+	sfx string
+	aug string
+	pos nlpgo.POSId
+}
+
+// induced is a RuleTransform with its reliability on the training pairs
+type induced struct {
+	rt        RuleTransform
+	support   int
+	precision float64
+}
+
+// InduceRules learns the suffix rules from the word pairs. For each pair the
+// ending which differs from the lemma becomes the rule affix, and the lemma
+// ending becomes the transform Augment, e.g. "tried" -> "try" yields the "ied"
+// affix with Cutoff 3 and Augment "y". The pairs of the same affix and POS
+// make one Rule.
+//
+// A transform correct for at least the min precision share of the training
+// forms it matches goes without context. Otherwise the chars before the affix
+// (up to the max context) which make it reliable are added as a ReBefore
+// regexp, e.g. `(?:pp|tt)ing$`, and the rest is covered by a context-free
+// fallback. The transforms and rules explaining less than min support pairs
+// are dropped.
+//
+// The transforms are ordered by their precision and support, the rules are
+// ordered likewise except that a rule always precedes the ones whose affix is
+// its suffix, e.g. "ies" goes before "s". The pairs without a common prefix
+// (e.g. "went" -> "go") and the identical ones are skipped.
+func InduceRules(pairs []WordPair, opts ...InduceOption) []Rule {
+	in := &inducer{minSupport: 2, minPrecision: 0.9, maxContext: 3}
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	// Group the pairs by the change
+	groups := make(map[change][]WordPair)
+	var changes []change
+	for _, p := range pairs {
+		sfx, aug, ok := splitChange(p.Form, p.Lemma)
+		if !ok {
+			continue
+		}
+		ch := change{sfx: sfx, aug: aug, pos: p.Pos}
+		if _, ok := groups[ch]; !ok {
+			changes = append(changes, ch)
+		}
+		groups[ch] = append(groups[ch], p)
+	}
+
+	// Learn the transforms per affix and POS
+	type ruleKey struct {
+		sfx string
+		pos nlpgo.POSId
+	}
+	byRule := make(map[ruleKey]*Rule)
+	ts := make(map[*Rule][]induced)
+	var rules []*Rule
+	for _, ch := range changes {
+		ok := groups[ch]
+		if len(ok) < in.minSupport {
+			continue
+		}
+
+		// All the training forms of the POS with the affix
+		var matched []WordPair
+		for _, p := range pairs {
+			if p.Pos == ch.pos && strings.HasSuffix(p.Form, ch.sfx) && len(p.Form) > len(ch.sfx) {
+				matched = append(matched, p)
+			}
+		}
+
+		tt := in.transforms(ch, ok, matched)
+		if len(tt) == 0 {
+			continue
+		}
+
+		k := ruleKey{ch.sfx, ch.pos}
+		r := byRule[k]
+		if r == nil {
+			r = &Rule{Affix: ch.sfx, Pos: []nlpgo.POSId{ch.pos}}
+			byRule[k] = r
+			rules = append(rules, r)
+		}
+		ts[r] = append(ts[r], tt...)
+	}
+
+	// Order the transforms and the rules by reliability
+	best := make(map[*Rule]induced)
+	for _, r := range rules {
+		tt := ts[r]
+		sort.SliceStable(tt, func(i, j int) bool {
+			return tt[i].better(tt[j])
+		})
+		for _, t := range tt {
+			r.Transforms = append(r.Transforms, t.rt)
+		}
+		best[r] = tt[0]
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return best[rules[i]].better(best[rules[j]])
+	})
+
+	// Move the longer affixes before their suffixes
+	var res []Rule
+	for _, r := range rules {
+		at := len(res)
+		for i := range res {
+			if len(res[i].Affix) < len(r.Affix) && strings.HasSuffix(r.Affix, res[i].Affix) {
+				at = i
+				break
+			}
+		}
+		res = append(res, Rule{})
+		copy(res[at+1:], res[at:])
+		res[at] = *r
+	}
+
+	return res
+}
+
+// splitChange returns the form ending to cut off and the lemma ending to
+// augment, ok is false if the form and lemma are equal or share no prefix
+func splitChange(form, lemma string) (sfx, aug string, ok bool) {
+	n := 0
+	for n < len(form) && n < len(lemma) {
+		r1, s1 := utf8.DecodeRuneInString(form[n:])
+		r2, _ := utf8.DecodeRuneInString(lemma[n:])
+		if r1 != r2 {
+			break
+		}
+		n += s1
+	}
+
+	if n == 0 || n == len(form) {
+		return "", "", false
+	}
+
+	return form[n:], lemma[n:], true
+}
+
+// transforms learns the transforms of the change from the pairs it explains
+// and all the training forms of the POS ending with the affix
+func (in *inducer) transforms(ch change, ok, matched []WordPair) (tt []induced) {
+	cutoff := utf8.RuneCountInString(ch.sfx)
+	apply := func(form string) string {
+		return form[:len(form)-len(ch.sfx)] + ch.aug
+	}
+	correct := func(p WordPair) bool {
+		return apply(p.Form) == p.Lemma
+	}
+
+	precision := func(ctx string) (n int, prec float64) {
+		var all int
+		for _, p := range matched {
+			if !strings.HasSuffix(p.Form, ctx+ch.sfx) {
+				continue
+			}
+			all++
+			if correct(p) {
+				n++
+			}
+		}
+		if all == 0 {
+			return 0, 0
+		}
+		return n, float64(n) / float64(all)
+	}
+
+	if n, prec := precision(""); prec >= in.minPrecision {
+		return []induced{{rt: RuleTransform{Cutoff: cutoff, Augment: ch.aug}, support: n, precision: prec}}
+	}
+
+	// Find the reliable contexts, the shortest first
+	left := ok
+	for k := 1; k <= in.maxContext && len(left) >= in.minSupport; k++ {
+		var ctxs []string
+		seen := make(map[string]bool)
+		chosen := make(map[string]bool)
+		var support int
+		var all float64
+		for _, p := range left {
+			ctx, ok := contextOf(p.Form, ch.sfx, k)
+			if !ok || seen[ctx] {
+				continue
+			}
+			seen[ctx] = true
+
+			n, prec := precision(ctx)
+			if n >= in.minSupport && prec >= in.minPrecision {
+				chosen[ctx] = true
+				ctxs = append(ctxs, regexp.QuoteMeta(ctx))
+				support += n
+				all += float64(n) / prec
+			}
+		}
+		if len(ctxs) == 0 {
+			continue
+		}
+
+		sort.Strings(ctxs)
+		expr := ctxs[0]
+		if len(ctxs) > 1 {
+			expr = `(?:` + strings.Join(ctxs, "|") + `)`
+		}
+		tt = append(tt, induced{
+			rt: RuleTransform{
+				Cutoff:   cutoff,
+				Augment:  ch.aug,
+				ReBefore: regexp.MustCompile(expr + regexp.QuoteMeta(ch.sfx) + `$`),
+			},
+			support:   support,
+			precision: float64(support) / all,
+		})
+
+		var rest []WordPair
+		for _, p := range left {
+			if ctx, ok := contextOf(p.Form, ch.sfx, k); !ok || !chosen[ctx] {
+				rest = append(rest, p)
+			}
+		}
+		left = rest
+	}
+
+	// The fallback for the pairs without a reliable context
+	if len(left) >= in.minSupport {
+		_, prec := precision("")
+		tt = append(tt, induced{rt: RuleTransform{Cutoff: cutoff, Augment: ch.aug}, support: len(left), precision: prec})
+	}
+
+	return
+}
+
+// contextOf returns k chars of the form before the affix, ok is false if the
+// form is too short
+func contextOf(form, sfx string, k int) (ctx string, ok bool) {
+	stem := form[:len(form)-len(sfx)]
+	i := len(stem)
+	for ; k > 0 && i > 0; k-- {
+		_, size := utf8.DecodeLastRuneInString(stem[:i])
+		i -= size
+	}
+	if k > 0 {
+		return "", false
+	}
+
+	return stem[i:], true
+}
+
+// better reports whether the transform is more reliable than o
+func (t induced) better(o induced) bool {
+	if t.precision != o.precision {
+		return t.precision > o.precision
+	}
+	return t.support > o.support
+}
@@ -0,0 +1,152 @@
+package lm
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+const testWordPairs = `# form	lemma	POS
+walked	walk	VBD
+talked	talk	VBD
+played	play	VBD
+jumped	jump	VBD
+hoped	hope	VBD
+liked	like	VBD
+baked	bake	VBD
+tried	try	VBD
+cried	cry	VBD
+stopped	stop	VBD
+dropped	drop	VBD
+planned	plan	VBD
+walking	walk	VBG
+talking	talk	VBG
+hoping	hope	VBG
+baking	bake	VBG
+stopping	stop	VBG
+dropping	drop	VBG
+shopping	shop	VBG
+running	run	VBG
+planning	plan	VBG
+typing	type	VBG
+wiping	wipe	VBG
+
+cats	cat	NNS
+dogs	dog	NNS
+boxes	box	NNS
+foxes	fox	NNS
+churches	church	NNS
+cities	city	NNS
+parties	party	NNS
+went	go	VBD
+cut	cut	44
+`
+
+func TestReadWordPairs(t *testing.T) {
+	assert := assert.New(t)
+
+	pairs, err := ReadWordPairs(strings.NewReader(testWordPairs))
+	assert.NoError(err)
+	assert.Len(pairs, 32)
+	assert.Equal(WordPair{Form: "walked", Lemma: "walk", Pos: nlpgo.PosIdVbd}, pairs[0])
+	assert.Equal(WordPair{Form: "cut", Lemma: "cut", Pos: nlpgo.PosIdVbd}, pairs[31])
+
+	_, err = ReadWordPairs(strings.NewReader("walked\twalk\n"))
+	assert.EqualError(err, "line 1: form, lemma and POS expected, got 2 fields")
+	_, err = ReadWordPairs(strings.NewReader("\nwalked\twalk\tVBX\n"))
+	assert.EqualError(err, `line 2: invalid POS "VBX"`)
+}
+
+func TestInduceRules(t *testing.T) {
+	assert := assert.New(t)
+
+	pairs, err := ReadWordPairs(strings.NewReader(testWordPairs))
+	assert.NoError(err)
+
+	rules := InduceRules(pairs)
+
+	var b bytes.Buffer
+	assert.NoError(WriteRules(&b, rules))
+	assert.Equal(`rule suffix ping pos=VBG
+	transform cutoff=4 before=`+"`pping$`"+`
+
+rule suffix ied pos=VBD
+	transform cutoff=3 augment=y
+
+rule suffix ped pos=VBD
+	transform cutoff=3 before=`+"`pped$`"+`
+
+rule suffix ed pos=VBD
+	transform cutoff=2 before=`+"`lked$`"+`
+	transform cutoff=2
+
+rule suffix ning pos=VBG
+	transform cutoff=4
+
+rule suffix ing pos=VBG
+	transform cutoff=3 before=`+"`lking$`"+`
+	transform cutoff=3 augment=e
+
+rule suffix ies pos=NNS
+	transform cutoff=3 augment=y
+
+rule suffix es pos=NNS
+	transform cutoff=2 before=`+"`xes$`"+`
+
+rule suffix s pos=NNS
+	transform cutoff=1
+
+rule suffix d pos=VBD
+	transform cutoff=1
+`, b.String())
+
+	assert.Empty(LintRules(rules), "Expect the longer affixes first")
+
+	// The induced rules on the unseen words
+	lmChecker := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"mop":   {nlpgo.PosIdVerb},
+		"marry": {nlpgo.PosIdVerb},
+		"save":  {nlpgo.PosIdVerb},
+		"mix":   {nlpgo.PosIdNoun, nlpgo.PosIdVerb},
+		"bug":   {nlpgo.PosIdNoun},
+		"lady":  {nlpgo.PosIdNoun},
+	})
+	lzr := NewLemmatizer(lmChecker, []LmResolver{NewSuffixRuleResolver(rules, lmChecker)})
+	for _, v := range [][]string{
+		{"mopping", "mop"},
+		{"mopped", "mop"},
+		{"married", "marry"},
+		{"saving", "save"},
+		{"saved", "save"},
+		{"mixes", "mix"},
+		{"bugs", "bug"},
+		{"ladies", "lady"},
+	} {
+		assert.Equal(v[1], lzr.Lemmatize(v[0]).Val, v[0])
+	}
+
+	// Options
+	rules = InduceRules(pairs, WithMinSupport(1), WithMinPrecision(1), WithMaxContext(1))
+	assert.Equal(Rule{
+		Affix: "es",
+		Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+		Transforms: []RuleTransform{
+			{Cutoff: 2, ReBefore: regexp.MustCompile(`(?:h|x)es$`)},
+		},
+	}, rules[ruleIndex(rules, "es")])
+
+	assert.Empty(InduceRules(nil))
+}
+
+func ruleIndex(rules []Rule, affix string) int {
+	for i, r := range rules {
+		if r.Affix == affix {
+			return i
+		}
+	}
+	return -1
+}
@@ -11,10 +11,28 @@ func NewLemmaIndex(data map[string][]nlpgo.POSId) LemmaIndex {
 	return LemmaIndex{idx: data}
 }
 
-func (l LemmaIndex) lookup(text string) Lemma {
+// Lookup returns the lemma with its POS'es if text is in the index
+func (l LemmaIndex) Lookup(text string) Lemma {
 	if v, ok := l.idx[text]; ok {
 		return Lemma{Val: text, Pos: v}
 	}
 
 	return Lemma{}
 }
+
+// lookupFor looks up text in lc and narrows the found lemma POS'es to the ones
+// allowed by acc. If the text is not found or no POS is allowed, the found
+// value is returned along with the reject reason.
+func lookupFor(lc LmChecker, text string, acc *LemmaAccumulator) (Lemma, Reject) {
+	l := lc.Lookup(text)
+	if l.Val == "" {
+		return l, RejectLookup
+	}
+
+	pp, ok := acc.FilterPos(l.Pos)
+	if !ok {
+		return l, RejectPos
+	}
+
+	return Lemma{Val: l.Val, Pos: pp}, RejectNone
+}
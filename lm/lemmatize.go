@@ -1,6 +1,9 @@
 package lm
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/timurgarif/nlpgo"
 )
 
@@ -11,14 +14,60 @@ type Lemma struct {
 	Pos []nlpgo.POSId
 }
 
-// A map type to accumulate lemma candidates
-type LemmaAccumulator map[string]map[nlpgo.POSId]struct{}
+// LemmaAccumulator accumulates lemma candidates and keeps track of their rank.
+// Each call to Add (or Set) opens a new rank, so candidates are ranked in the
+// order they were added: the resolver order first, then the rule/exception
+// order within a resolver. Candidates added with AddTied (or SetTied) share
+// the rank of the preceding Add and are ordered by the Lemmatizer
+// tie-breaker (if any).
+//
+// The accumulator also carries an optional POS constraint of the
+// lemmatization call. Resolvers should drop the candidates which FilterPos
+// rejects.
+type LemmaAccumulator struct {
+	ents    []accEntry
+	rank    int
+	tie     TieBreaker
+	allowed []nlpgo.POSId
+	// Index of the currently applied resolver
+	resolver int
+	tracer   Tracer
+	ranking  RankFunc
+}
+
+type accEntry struct {
+	c    Candidate
+	rank int
+}
+
+// TieBreaker reports whether the tied candidate a must precede b.
+type TieBreaker func(a, b Lemma) bool
+
+// RankFunc reports whether candidate a must precede b. It overrides the
+// default rank order, which is kept for the candidates it considers equal.
+type RankFunc func(a, b Candidate) bool
+
+// NewLemmaAccumulator creates an empty LemmaAccumulator
+func NewLemmaAccumulator() *LemmaAccumulator {
+	return &LemmaAccumulator{}
+}
 
 // LmChecker provides an interface to check if a lemma exist. What is considered
 // to be lemma is implementation specific.
+// LemmaIndex is the in-memory implementation, other stores can be plugged in
+// by implementing Lookup or with LmCheckerFunc.
 type LmChecker interface {
-	// Lemma with the zero value shall be returned if `text` is not found
-	lookup(text string) Lemma
+	// Lemma with the zero value shall be returned if `text` is not found.
+	// Lookup must be safe for concurrent use if the Lemmatizer is shared
+	// between goroutines.
+	Lookup(text string) Lemma
+}
+
+// LmCheckerFunc is an adapter to use a function as an LmChecker
+type LmCheckerFunc func(text string) Lemma
+
+func (f LmCheckerFunc) Lookup(text string) Lemma {
+	return f(text)
 }
 
 // LmResolver is a way to apply a strategy to the
@@ -26,104 +75,380 @@ type LmChecker interface {
 type LmResolver interface {
 	// Resolves lemmata and adds them to acc. It should stop resolving if total
 	// acc size >= max
-	Resolve(word string, acc LemmaAccumulator, max int)
+	Resolve(word string, acc *LemmaAccumulator, max int)
 }
 
-// Lemmatizer type implements lemmatization
+// Lemmatizer type implements lemmatization.
+// Lemmatizer is safe for concurrent use by multiple goroutines as long as its
+// LmChecker and resolvers are: every call gets its own LemmaAccumulator
+// from a pool.
 type Lemmatizer struct {
-	lc  LmChecker
-	rs  []LmResolver
-	acc LemmaAccumulator
+	lc   LmChecker
+	rs   []LmResolver
+	accs *sync.Pool
+
+	// Options
+	max           int
+	norm          Normalizer
+	restoreCase   bool
+	cache         *resultCache
+	tracer        Tracer
+	resolveLemmas bool
+	tie           TieBreaker
+	ranking       RankFunc
+	workers       int
 }
 
-// LmOption defines a functional option type for the Lemmatizer
-type LmOption func(*Lemmatizer)
+// DefaultMaxCandidates is the number of candidates returned if the max
+// argument is not positive and WithMaxCandidates is not set.
+const DefaultMaxCandidates = 5
 
 func NewLemmatizer(lkpr LmChecker, resolvers []LmResolver, opts ...LmOption) *Lemmatizer {
-	return &Lemmatizer{lc: lkpr, rs: resolvers, acc: make(LemmaAccumulator)}
+	l := &Lemmatizer{
+		lc:            lkpr,
+		rs:            resolvers,
+		max:           DefaultMaxCandidates,
+		resolveLemmas: true,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	tie, ranking := l.tie, l.ranking
+	l.accs = &sync.Pool{
+		New: func() interface{} {
+			acc := NewLemmaAccumulator()
+			acc.tie = tie
+			acc.ranking = ranking
+			return acc
+		},
+	}
+
+	return l
 }
 
 // Lemmatize returns the first resolved lemma
 func (l Lemmatizer) Lemmatize(word string) Lemma {
-	cc := l.LemmaCandidates(word, 1)
+	cc := l.CandidatesFor(word, nil, 1)
 	if len(cc) > 0 {
-		return cc[0]
+		return cc[0].Lemma
 	}
 
 	return Lemma{}
 }
 
-// LemmaCandidates returns up to `max` lemma candidates for
-// the given word.
-// The order of the candidates in the returning array depends on:
-//	- the order of the resolvers passed in to the Lemmatizer constructor
-//	- the internal policy of each resolver
-func (l Lemmatizer) LemmaCandidates(word string, max int) (candidates []Lemma) {
+// Candidates is like LemmaCandidates but returns the candidates with their
+// provenance and score.
+func (l Lemmatizer) Candidates(word string, max int) []Candidate {
+	return l.CandidatesFor(word, nil, max)
+}
+
+// CandidatesFor is like LemmaCandidatesFor but returns the candidates with
+// their provenance and score.
+func (l Lemmatizer) CandidatesFor(word string, allowed []nlpgo.POSId, max int) []Candidate {
 	if max <= 0 {
-		max = 5
+		max = l.max
+	}
+	surface := word
+	word = l.Normalize(word)
+
+	var cc []Candidate
+	if l.cache == nil || word == "" {
+		cc = l.resolve(word, allowed, max, l.tracer)
+	} else {
+		key := cacheKey(word, allowed, max)
+		var ok bool
+		if cc, ok = l.cache.get(key); !ok {
+			cc = l.resolve(word, allowed, max, l.tracer)
+			l.cache.put(key, cc)
+		}
 	}
 
+	l.setSurface(cc, surface)
+
+	return cc
+}
+
+// setSurface sets the input word to the candidates and restores the lemma case
+// if required
+func (l Lemmatizer) setSurface(cc []Candidate, surface string) {
+	for i := range cc {
+		cc[i].Surface = surface
+		if l.restoreCase {
+			cc[i].Val = RestoreCase(surface, cc[i].Val)
+		}
+	}
+}
+
+// Normalize returns the word as it's looked up by the Lemmatizer, i.e.
+// transformed by the normalizer set with WithNormalizer.
+func (l Lemmatizer) Normalize(word string) string {
+	if l.norm == nil {
+		return word
+	}
+	return l.norm(word)
+}
+
+// resolve runs the lemmatization pipeline on the (normalized) word
+func (l Lemmatizer) resolve(word string, allowed []nlpgo.POSId, max int, tracer Tracer) (candidates []Candidate) {
 	if word == "" {
 		return
 	}
 
-	l.acc.clear()
+	acc := l.accs.Get().(*LemmaAccumulator)
+	defer func() {
+		acc.clear()
+		l.accs.Put(acc)
+	}()
+	acc.allowed = allowed
+	acc.tracer = tracer
 
 	// First check if input is already a lemma
-	if lm := l.lc.lookup(word); lm.Val != "" {
-		l.acc.Set(lm.Val, lm.Pos)
+	acc.resolver = -1
+	lm, rej := lookupFor(l.lc, word, acc)
+	if acc.Tracing() {
+		acc.Trace(TraceStep{
+			Kind:      StepLookup,
+			Word:      word,
+			Transform: -1,
+			Candidate: lm.Val,
+			Pos:       lm.Pos,
+			Reject:    rej,
+		})
 	}
-
-	if len(l.acc) >= max {
-		candidates = l.acc.lemmata(max)
-		return
+	if rej == RejectNone {
+		acc.Add(Candidate{
+			Lemma:     lm,
+			Source:    SourceLemma,
+			Transform: -1,
+			Score:     ScoreLemma,
+		})
 	}
 
 	// Apply resolvers
-	for _, r := range l.rs {
-		r.Resolve(word, l.acc, max)
-		if len(l.acc) >= max {
-			candidates = l.acc.lemmata(max)
-			return
+	for i, r := range l.rs {
+		if acc.Len() >= max || (!l.resolveLemmas && rej == RejectNone) {
+			break
 		}
+		acc.resolver = i
+		r.Resolve(word, acc, max)
+	}
+
+	candidates = acc.candidates(max)
+
+	if acc.Tracing() {
+		for _, c := range candidates {
+			acc.Trace(TraceStep{
+				Kind:      StepChoice,
+				Resolver:  c.Resolver,
+				Word:      word,
+				Affix:     c.Affix,
+				Transform: c.Transform,
+				Candidate: c.Val,
+				Pos:       c.Pos,
+			})
+		}
+	}
+
+	return
+}
+
+// LemmatizeAs returns the first resolved lemma compatible with pos. The pos
+// can be either a base POS (e.g. nlpgo.PosIdVerb) or a form (e.g.
+// nlpgo.PosIdVbz).
+func (l Lemmatizer) LemmatizeAs(word string, pos nlpgo.POSId) Lemma {
+	cc := l.LemmaCandidatesFor(word, []nlpgo.POSId{pos}, 1)
+	if len(cc) > 0 {
+		return cc[0]
+	}
+
+	return Lemma{}
+}
+
+// LemmaCandidates returns up to `max` lemma candidates for
+// the given word.
+// The order of the candidates in the returning array is stable and depends on:
+//	- the input word itself if it is a lemma already
+//	- the order of the resolvers passed in to the Lemmatizer constructor
+//	- the internal policy of each resolver (e.g. rule or exception order)
+//	- the tie-breaker for the candidates of the same rank
+//	- the ranking strategy if set with WithRanking
+func (l Lemmatizer) LemmaCandidates(word string, max int) (candidates []Lemma) {
+	return l.LemmaCandidatesFor(word, nil, max)
+}
+
+// LemmaCandidatesFor returns up to `max` lemma candidates for the given word
+// whose POS'es are compatible with the allowed ones (see FilterPos). The POS
+// list of each candidate is narrowed to the compatible POS'es.
+// If allowed is empty, it's equivalent to LemmaCandidates.
+func (l Lemmatizer) LemmaCandidatesFor(word string, allowed []nlpgo.POSId, max int) (candidates []Lemma) {
+	cc := l.CandidatesFor(word, allowed, max)
+	if len(cc) == 0 {
+		return
+	}
+	candidates = make([]Lemma, len(cc))
+	for i, c := range cc {
+		candidates[i] = c.Lemma
+	}
+
+	return
+}
+
+// Len returns the number of distinct lemma candidates accumulated
+func (acc *LemmaAccumulator) Len() int {
+	return len(acc.ents)
+}
+
+func (acc *LemmaAccumulator) clear() {
+	acc.ents = acc.ents[:0]
+	acc.rank = 0
+	acc.allowed = nil
+	acc.resolver = 0
+	acc.tracer = nil
+}
+
+func (acc *LemmaAccumulator) candidates(max int) (cc []Candidate) {
+	// The input lemma is added first, before the ranking moves it
+	inputIsLemma := len(acc.ents) > 0 && acc.ents[0].c.Source == SourceLemma
+
+	// The entries are added in the rank order, so they are to be sorted only
+	// to break the ties or to rank them with RankFunc
+	ents := acc.ents
+	if acc.tie != nil || acc.ranking != nil {
+		ents = make([]accEntry, len(acc.ents))
+		copy(ents, acc.ents)
+	}
+
+	if acc.tie != nil {
+		sort.SliceStable(ents, func(i, j int) bool {
+			if ents[i].rank != ents[j].rank {
+				return ents[i].rank < ents[j].rank
+			}
+			return acc.tie(ents[i].c.Lemma, ents[j].c.Lemma)
+		})
+	}
+	if acc.ranking != nil {
+		sort.SliceStable(ents, func(i, j int) bool {
+			return acc.ranking(ents[i].c, ents[j].c)
+		})
+	}
+
+	if len(ents) < max {
+		max = len(ents)
+	}
+	if max == 0 {
+		return
+	}
+	cc = make([]Candidate, max)
+	for i := range cc {
+		cc[i] = ents[i].c
+		cc[i].InputIsLemma = inputIsLemma
 	}
 
-	candidates = l.acc.lemmata(max)
 	return
 }
 
-func (acc LemmaAccumulator) clear() {
-	// Optimized by the compiler since Go 1.11
-	for k := range acc {
-		delete(acc, k)
+func (acc *LemmaAccumulator) lemmata(max int) (ll []Lemma) {
+	for _, c := range acc.candidates(max) {
+		ll = append(ll, c.Lemma)
 	}
+
+	return
+}
+
+// Set adds a lemma candidate with a new rank. If the lemma has been added
+// already, its POS'es are merged and the original rank and provenance are
+// kept.
+func (acc *LemmaAccumulator) Set(lemma string, pp []nlpgo.POSId) {
+	acc.Add(acc.unknown(lemma, pp))
+}
+
+// SetTied adds a lemma candidate with the rank of the preceding Set call.
+func (acc *LemmaAccumulator) SetTied(lemma string, pp []nlpgo.POSId) {
+	acc.AddTied(acc.unknown(lemma, pp))
+}
+
+// Add is like Set but keeps the candidate provenance and score.
+// The Resolver field is filled in by the accumulator.
+func (acc *LemmaAccumulator) Add(c Candidate) {
+	acc.rank++
+	acc.add(c)
+}
+
+// AddTied is like SetTied but keeps the candidate provenance and score.
+func (acc *LemmaAccumulator) AddTied(c Candidate) {
+	acc.add(c)
 }
 
-func (acc LemmaAccumulator) lemmata(max int) (ll []Lemma) {
-	i := 0
+func (acc *LemmaAccumulator) unknown(lemma string, pp []nlpgo.POSId) Candidate {
+	return Candidate{
+		Lemma:     Lemma{Val: lemma, Pos: pp},
+		Source:    SourceUnknown,
+		Transform: -1,
+		Score:     ScoreUnknown,
+	}
+}
 
-	for k, v := range acc {
-		var pos []nlpgo.POSId
-		for p := range v {
-			pos = append(pos, p)
+func (acc *LemmaAccumulator) add(c Candidate) {
+	i := acc.index(c.Val)
+	if i < 0 {
+		i = len(acc.ents)
+		acc.ents = append(acc.ents, accEntry{c: c, rank: acc.rank})
+		acc.ents[i].c.Resolver = acc.resolver
+		// The POS'es are merged below into a slice owned by the accumulator
+		acc.ents[i].c.Pos = nil
+		if len(c.Pos) > 0 {
+			acc.ents[i].c.Pos = make([]nlpgo.POSId, 0, len(c.Pos))
 		}
-		ll = append(ll, Lemma{Val: k, Pos: pos})
+	}
 
-		i++
-		if i >= max {
-			return
+	e := &acc.ents[i]
+	for _, p := range c.Pos {
+		if !hasPos(e.c.Pos, p) {
+			e.c.Pos = append(e.c.Pos, p)
 		}
 	}
+}
 
-	return
+// index returns the index of the lemma entry, -1 if none. There are a few
+// candidates per call, so the scan is cheaper than a map.
+func (acc *LemmaAccumulator) index(lemma string) int {
+	for i := range acc.ents {
+		if acc.ents[i].c.Val == lemma {
+			return i
+		}
+	}
+	return -1
 }
 
-func (acc LemmaAccumulator) Set(lemma string, pp []nlpgo.POSId) {
-	if _, ok := acc[lemma]; !ok {
-		acc[lemma] = map[nlpgo.POSId]struct{}{}
+// FilterPos returns the POS'es of pp compatible with the POS constraint of
+// the lemmatization call, and whether there are any. A POS is compatible with
+// an allowed one if they are equal or one is a form of the other (see
+// nlpgo.POSId.HasForm), e.g. VBZ is compatible with VERB and vice versa.
+// Without a constraint pp is returned as is.
+func (acc *LemmaAccumulator) FilterPos(pp []nlpgo.POSId) ([]nlpgo.POSId, bool) {
+	if len(acc.allowed) == 0 {
+		return pp, true
 	}
 
+	var fpp []nlpgo.POSId
 	for _, p := range pp {
-		acc[lemma][p] = struct{}{}
+		for _, a := range acc.allowed {
+			if p == a || p.HasForm(a) || a.HasForm(p) {
+				fpp = append(fpp, p)
+				break
+			}
+		}
+	}
+
+	return fpp, len(fpp) > 0
+}
+
+func hasPos(pp []nlpgo.POSId, p nlpgo.POSId) bool {
+	for _, v := range pp {
+		if v == p {
+			return true
+		}
 	}
+	return false
 }
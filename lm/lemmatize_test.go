@@ -1,7 +1,9 @@
 package lm
 
 import (
+	"regexp"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -154,3 +156,303 @@ func TestLemmaCandidates(t *testing.T) {
 		assert.Equal(expected, actual)
 	}
 }
+
+func TestLemmaCandidatesOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"leaves": {nlpgo.PosIdNoun},
+		"leaf":   {nlpgo.PosIdNoun},
+		"leave":  {nlpgo.PosIdVerb, nlpgo.PosIdNoun},
+	})
+	excpIdx := map[string][]Lemma{
+		"leaves": {
+			{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdNns, nlpgo.PosIdVbz}},
+			{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+		},
+	}
+	rules := []Rule{
+		{
+			Affix:      "s",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbz},
+			Transforms: []RuleTransform{{Cutoff: 1}},
+		},
+	}
+	resolvers := []LmResolver{
+		NewSuffixRuleResolver(rules, lmIdx),
+		NewExceptionResolver(excpIdx),
+	}
+	alphabetical := func(a, b Lemma) bool { return a.Val < b.Val }
+
+	cases := []struct {
+		l   *Lemmatizer
+		out []string
+		msg string
+	}{
+		{
+			l:   NewLemmatizer(lmIdx, resolvers),
+			out: []string{"leaves", "leave", "leaf"},
+			msg: "Expect input lemma, then resolver order, then exception order",
+		},
+		{
+			l:   NewLemmatizer(lmIdx, resolvers[1:]),
+			out: []string{"leaves", "leave", "leaf"},
+			msg: "Expect exception alternatives keep the data order by default",
+		},
+		{
+			l:   NewLemmatizer(lmIdx, resolvers[1:], WithTieBreaker(alphabetical)),
+			out: []string{"leaves", "leaf", "leave"},
+			msg: "Expect tie-breaker orders exception alternatives",
+		},
+		{
+			l:   NewLemmatizer(lmIdx, resolvers, WithTieBreaker(alphabetical)),
+			out: []string{"leaves", "leave", "leaf"},
+			msg: "Expect tie-breaker does not reorder candidates of different ranks",
+		},
+	}
+
+	for _, tt := range cases {
+		// Repeat to make sure the order does not depend on map iteration
+		for i := 0; i < 20; i++ {
+			var actual []string
+			for _, lm := range tt.l.LemmaCandidates("leaves", 10) {
+				actual = append(actual, lm.Val)
+			}
+			assert.Equal(tt.out, actual, tt.msg)
+		}
+	}
+}
+
+func TestLemmatizerConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := getCases()
+	for _, tt := range cases {
+		tt.lzCcOut = lmsort(tt.lzCcOut)
+	}
+
+	// All the cases share the lemmatizers, so run them all in parallel many
+	// times. Run with -race to detect shared state.
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				for _, tt := range cases {
+					actual := lmsort(tt.l.LemmaCandidates(tt.in, 10))
+					assert.Equal(tt.lzCcOut, actual)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLemmaCandidatesFor(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"leaf":  {nlpgo.PosIdNoun},
+		"leave": {nlpgo.PosIdVerb, nlpgo.PosIdNoun},
+		"walk":  {nlpgo.PosIdVerb, nlpgo.PosIdNoun},
+		"walks": {nlpgo.PosIdNoun},
+	})
+	excpIdx := map[string][]Lemma{
+		"leaves": {
+			{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdNns, nlpgo.PosIdVbz}},
+		},
+	}
+	rules := []Rule{
+		{
+			Affix:      "s",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdNns, nlpgo.PosIdVbz},
+			Transforms: []RuleTransform{{Cutoff: 1}},
+		},
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{
+		NewExceptionResolver(excpIdx),
+		NewSuffixRuleResolver(rules, lmIdx),
+	})
+
+	cases := []struct {
+		in      string
+		allowed []nlpgo.POSId
+		out     []Lemma
+		msg     string
+	}{
+		{
+			in:      "leaves",
+			allowed: []nlpgo.POSId{nlpgo.PosIdVerb},
+			out:     []Lemma{{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}},
+			msg:     "Expect only verb lemma for verb base POS",
+		},
+		{
+			in:      "leaves",
+			allowed: []nlpgo.POSId{nlpgo.PosIdVbz},
+			out:     []Lemma{{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}},
+			msg:     "Expect only verb lemma for verb form POS",
+		},
+		{
+			in:      "leaves",
+			allowed: []nlpgo.POSId{nlpgo.PosIdNns},
+			out: []Lemma{
+				{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+				{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			},
+			msg: "Expect noun lemmata narrowed to the noun form POS",
+		},
+		{
+			in:      "leaves",
+			allowed: []nlpgo.POSId{nlpgo.PosIdAdj},
+			out:     nil,
+			msg:     "Expect no lemma for incompatible POS",
+		},
+		{
+			in:      "walks",
+			allowed: []nlpgo.POSId{nlpgo.PosIdVerb},
+			out:     []Lemma{{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}},
+			msg:     "Expect input lemma of another POS skipped and rule form narrowed",
+		},
+		{
+			in:      "walks",
+			allowed: []nlpgo.POSId{nlpgo.PosIdNoun},
+			out: []Lemma{
+				{Val: "walks", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+				{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			},
+			msg: "Expect input lemma and rule lemma for noun POS",
+		},
+		{
+			in: "walks",
+			out: []Lemma{
+				{Val: "walks", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+				{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbz, nlpgo.PosIdNns}},
+			},
+			msg: "Expect no constraint without allowed POS'es",
+		},
+	}
+
+	for _, tt := range cases {
+		assert.Equal(tt.out, l.LemmaCandidatesFor(tt.in, tt.allowed, 10), tt.msg)
+	}
+
+	assert.Equal(Lemma{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+		l.LemmatizeAs("leaves", nlpgo.PosIdVerb))
+	assert.Equal(Lemma{}, l.LemmatizeAs("leaves", nlpgo.PosIdAdv))
+}
+
+func TestCandidates(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"leaves": {nlpgo.PosIdNoun},
+		"leaf":   {nlpgo.PosIdNoun},
+		"leave":  {nlpgo.PosIdVerb},
+		"walk":   {nlpgo.PosIdVerb},
+	})
+	excpIdx := map[string][]Lemma{
+		"leaves": {{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}}},
+	}
+	rules := []Rule{
+		{
+			Affix: "s",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbz},
+			Transforms: []RuleTransform{
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`.ches$`)},
+				{Cutoff: 1},
+			},
+		},
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{
+		NewExceptionResolver(excpIdx),
+		NewSuffixRuleResolver(rules, lmIdx),
+	})
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:        Lemma{Val: "leaves", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+			Surface:      "leaves",
+			Source:       SourceLemma,
+			Resolver:     -1,
+			Transform:    -1,
+			InputIsLemma: true,
+			Score:        ScoreLemma,
+		},
+		{
+			Lemma:        Lemma{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			Surface:      "leaves",
+			Source:       SourceException,
+			Resolver:     0,
+			Transform:    -1,
+			InputIsLemma: true,
+			Score:        ScoreException,
+		},
+		{
+			Lemma:        Lemma{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+			Surface:      "leaves",
+			Source:       SourceRule,
+			Resolver:     1,
+			Affix:        "s",
+			Transform:    1,
+			InputIsLemma: true,
+			Score:        ScoreRule,
+		},
+	}, l.Candidates("leaves", 10))
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+			Surface:   "walks",
+			Source:    SourceRule,
+			Resolver:  1,
+			Affix:     "s",
+			Transform: 1,
+			Score:     ScoreRule,
+		},
+	}, l.Candidates("walks", 10), "Expect input is not a lemma")
+
+	assert.Equal("exception", SourceException.String())
+}
+
+// kvChecker is a test double of an external lemma store
+type kvChecker map[string]string
+
+func (c kvChecker) Lookup(text string) Lemma {
+	if _, ok := c[text]; !ok {
+		return Lemma{}
+	}
+	return Lemma{Val: text, Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+}
+
+func TestCustomLmChecker(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := []Rule{
+		{
+			Affix:      "s",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbz},
+			Transforms: []RuleTransform{{Cutoff: 1}},
+		},
+	}
+
+	var lookups []string
+	checkers := []LmChecker{
+		kvChecker{"walk": ""},
+		LmCheckerFunc(func(text string) Lemma {
+			lookups = append(lookups, text)
+			if text == "walk" {
+				return Lemma{Val: text, Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}
+			}
+			return Lemma{}
+		}),
+	}
+
+	for _, lc := range checkers {
+		l := NewLemmatizer(lc, []LmResolver{NewSuffixRuleResolver(rules, lc)})
+		assert.Equal(Lemma{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}, l.Lemmatize("walks"))
+		assert.Equal(Lemma{Val: "walk", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}, l.Lemmatize("walk"))
+	}
+	assert.Equal([]string{"walks", "walk", "walk"}, lookups)
+}
@@ -0,0 +1,283 @@
+package lm
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// LintSeverity defines how serious a LintIssue is
+type LintSeverity uint8
+
+const (
+	// The rule set works but likely not as intended
+	LintWarning LintSeverity = iota
+	// A part of the rule set is dead and can never yield a candidate
+	LintError
+)
+
+var lintSeverityNames = [...]string{
+	LintWarning: "warning",
+	LintError:   "error",
+}
+
+func (s LintSeverity) String() string {
+	if int(s) < len(lintSeverityNames) {
+		return lintSeverityNames[s]
+	}
+	return "unknown"
+}
+
+// LintIssue describes a problem found in a rule set by LintRules
+type LintIssue struct {
+	Severity LintSeverity
+	// Index of the Rule in the rule set
+	Rule int
+	// Affix of the Rule
+	Affix string
+	// Index of the RuleTransform within Rule.Transforms, -1 if the issue
+	// concerns the whole rule
+	Transform int
+	Msg       string
+}
+
+func (li LintIssue) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s: rule #%d %q", li.Severity, li.Rule, li.Affix)
+	if li.Transform >= 0 {
+		fmt.Fprintf(&b, " transform #%d", li.Transform)
+	}
+	fmt.Fprintf(&b, ": %s", li.Msg)
+
+	return b.String()
+}
+
+// LintRules statically checks the rule set and returns the issues found in the
+// rule order. The checks are:
+//   - a transform preceded by a context-free transform of the same rule which
+//     applies to any word it applies to. It's an error if both yield the same
+//     candidate, so the later one is dead, and a warning otherwise as the
+//     later one fires only if the earlier candidate is not a lemma, e.g. a
+//     fallback preceding the specific cases;
+//   - a rule preceded by a rule of the same kind whose affix is a suffix
+//     (a prefix for the prefix rules) of its affix, e.g. "s" before "es";
+//   - a non-zero MinValidLen having no effect as the Cutoff and HeadCutoff
+//     reject the shorter words anyway;
+//   - a ReBefore or Subst regexp of a suffix (prefix) rule which is not
+//     anchored with the affix at the word end (start), e.g. `.ches$` is fine
+//     for the "s" suffix while `ches` or `.ches$` for "es" are not.
+func LintRules(rules []Rule) []LintIssue {
+	var issues []LintIssue
+
+	for ri := range rules {
+		r := &rules[ri]
+		issue := func(sev LintSeverity, ti int, format string, args ...interface{}) {
+			issues = append(issues, LintIssue{
+				Severity:  sev,
+				Rule:      ri,
+				Affix:     r.Affix,
+				Transform: ti,
+				Msg:       fmt.Sprintf(format, args...),
+			})
+		}
+
+		for pi := 0; pi < ri; pi++ {
+			if p := &rules[pi]; p.shadows(r) {
+				issue(LintWarning, -1, "affix %q of the preceding rule #%d matches first", p.Affix, pi)
+				break
+			}
+		}
+
+		for ti := range r.Transforms {
+			rt := &r.Transforms[ti]
+
+			for pi := 0; pi < ti; pi++ {
+				p := &r.Transforms[pi]
+				if !p.covers(rt, r) {
+					continue
+				}
+				if p.sameOutput(rt, r) {
+					issue(LintError, ti, "unreachable, transform #%d yields the same candidate first", pi)
+				} else {
+					issue(LintWarning, ti, "shadowed by the context-free transform #%d", pi)
+				}
+				break
+			}
+
+			if cut := rt.cuts(r); rt.MinValidLen != 0 && rt.MinValidLen <= cut+1 {
+				issue(LintWarning, ti, "MinValidLen %d has no effect with the cutoff of %d chars", rt.MinValidLen, cut)
+			}
+
+			if msg := r.checkAnchor(rt.ReBefore); msg != "" {
+				issue(LintWarning, ti, "ReBefore %s", msg)
+			}
+			if msg := r.checkAnchor(rt.Subst); msg != "" {
+				issue(LintWarning, ti, "Subst %s", msg)
+			}
+		}
+	}
+
+	return issues
+}
+
+// shadows reports whether the rule matches every word the later rule o does,
+// so o is applied only if r yields no candidate
+func (r *Rule) shadows(o *Rule) bool {
+	if r.Kind != o.Kind || len(r.Affix) >= len(o.Affix) {
+		return false
+	}
+
+	switch r.Kind {
+	case AffixSuffix:
+		return strings.HasSuffix(o.Affix, r.Affix)
+	case AffixPrefix:
+		return strings.HasPrefix(o.Affix, r.Affix)
+	}
+
+	return false
+}
+
+// cuts returns the number of chars the transform detaches from the word
+func (rt *RuleTransform) cuts(r *Rule) int {
+	if rt.Subst != nil || r.Kind == AffixInfix {
+		return 0
+	}
+	return rt.Cutoff + rt.HeadCutoff
+}
+
+// covers reports whether the transform has no context and applies to every
+// word the later transform o does
+func (rt *RuleTransform) covers(o *RuleTransform, r *Rule) bool {
+	if rt.ReBefore != nil || rt.ReAfter != nil || rt.Subst != nil {
+		return false
+	}
+
+	return rt.minLen(r) <= o.minLen(r) && rt.cuts(r) <= o.cuts(r)
+}
+
+// minLen returns the minimal word length the transform applies to
+func (rt *RuleTransform) minLen(r *Rule) int {
+	if cut := rt.cuts(r); rt.MinValidLen <= cut {
+		return cut + 1
+	}
+	return rt.MinValidLen
+}
+
+// sameOutput reports whether both transforms yield the same candidate
+func (rt *RuleTransform) sameOutput(o *RuleTransform, r *Rule) bool {
+	if rt.Subst != nil || o.Subst != nil {
+		return false
+	}
+	if r.Kind == AffixInfix {
+		return rt.Augment == o.Augment
+	}
+
+	return rt.Cutoff == o.Cutoff && rt.Augment == o.Augment &&
+		rt.HeadCutoff == o.HeadCutoff && rt.HeadAugment == o.HeadAugment
+}
+
+// checkAnchor returns the problem description if the regexp of a suffix or a
+// prefix rule does not end or start with the affix, or "" if it's fine
+func (r *Rule) checkAnchor(re *regexp.Regexp) string {
+	if re == nil || (r.Kind != AffixSuffix && r.Kind != AffixPrefix) {
+		return ""
+	}
+
+	sre, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return fmt.Sprintf("`%s`: %v", re, err)
+	}
+
+	atEnd := r.Kind == AffixSuffix
+	anchor, want := syntax.OpEndText, fmt.Sprintf("`%s$`", regexp.QuoteMeta(r.Affix))
+	if !atEnd {
+		anchor, want = syntax.OpBeginText, fmt.Sprintf("`^%s`", regexp.QuoteMeta(r.Affix))
+	}
+
+	var lit string
+	if sre.Op == syntax.OpConcat && len(sre.Sub) > 1 {
+		subs := sre.Sub
+		edge := subs[0]
+		if atEnd {
+			edge = subs[len(subs)-1]
+		}
+		if edge.Op == anchor {
+			if atEnd {
+				subs = subs[:len(subs)-1]
+			} else {
+				subs = subs[1:]
+			}
+			lit, _ = literalEdge(&syntax.Regexp{Op: syntax.OpConcat, Sub: subs}, atEnd)
+		}
+	}
+
+	if atEnd && strings.HasSuffix(lit, r.Affix) || !atEnd && strings.HasPrefix(lit, r.Affix) {
+		return ""
+	}
+
+	return fmt.Sprintf("`%s` is not anchored with %s", re, want)
+}
+
+// literalEdge returns the literal text every match of the regexp ends with
+// (starts with if atEnd is false), and whether it's the whole match
+func literalEdge(re *syntax.Regexp, atEnd bool) (string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return "", true
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+		return string(re.Rune), true
+	case syntax.OpCapture:
+		return literalEdge(re.Sub[0], atEnd)
+	case syntax.OpConcat:
+		var lit string
+		for i := range re.Sub {
+			sub := re.Sub[i]
+			if atEnd {
+				sub = re.Sub[len(re.Sub)-1-i]
+			}
+			s, whole := literalEdge(sub, atEnd)
+			if atEnd {
+				lit = s + lit
+			} else {
+				lit += s
+			}
+			if !whole {
+				return lit, false
+			}
+		}
+		return lit, true
+	case syntax.OpAlternate:
+		lit, whole := literalEdge(re.Sub[0], atEnd)
+		for _, sub := range re.Sub[1:] {
+			s, w := literalEdge(sub, atEnd)
+			if s != lit || !w {
+				whole = false
+			}
+			lit = commonEdge(lit, s, atEnd)
+		}
+		return lit, whole
+	}
+
+	return "", false
+}
+
+// commonEdge returns the common suffix (prefix if atEnd is false) of a and b
+func commonEdge(a, b string, atEnd bool) string {
+	n := 0
+	for n < len(a) && n < len(b) {
+		if atEnd && a[len(a)-1-n] != b[len(b)-1-n] || !atEnd && a[n] != b[n] {
+			break
+		}
+		n++
+	}
+
+	if atEnd {
+		return a[len(a)-n:]
+	}
+	return a[:n]
+}
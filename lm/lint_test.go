@@ -0,0 +1,85 @@
+package lm
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintRules(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := []Rule{
+		{
+			Affix: "s",
+			Transforms: []RuleTransform{
+				{Cutoff: 1, MinValidLen: 1},
+				{Cutoff: 1, ReBefore: regexp.MustCompile(`.[^s]s$`)},
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`(ch|sh)es$`)},
+				{Cutoff: 1, MinValidLen: 4},
+				{Subst: regexp.MustCompile(`ies`), Replace: "y"},
+			},
+		},
+		{
+			Affix: "es",
+			Transforms: []RuleTransform{
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`.(x|z)es$`)},
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`.ches$`)},
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`(?i)es$`)},
+			},
+		},
+		{
+			Kind:  AffixPrefix,
+			Affix: "un",
+			Transforms: []RuleTransform{
+				{HeadCutoff: 2, ReBefore: regexp.MustCompile(`^un.`)},
+				{HeadCutoff: 2, ReBefore: regexp.MustCompile(`^u`)},
+				{HeadCutoff: 2, MinValidLen: 2},
+			},
+		},
+		{
+			Kind:  AffixInfix,
+			Affix: "zu",
+			Transforms: []RuleTransform{
+				{},
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`.`)},
+			},
+		},
+	}
+
+	assert.Equal([]LintIssue{
+		{LintWarning, 0, "s", 0, "MinValidLen 1 has no effect with the cutoff of 1 chars"},
+		{LintError, 0, "s", 1, "unreachable, transform #0 yields the same candidate first"},
+		{LintWarning, 0, "s", 2, "shadowed by the context-free transform #0"},
+		{LintError, 0, "s", 3, "unreachable, transform #0 yields the same candidate first"},
+		{LintWarning, 0, "s", 4, "Subst `ies` is not anchored with `s$`"},
+		{LintWarning, 1, "es", -1, `affix "s" of the preceding rule #0 matches first`},
+		{LintWarning, 1, "es", 2, "ReBefore `(?i)es$` is not anchored with `es$`"},
+		{LintWarning, 2, "un", 1, "ReBefore `^u` is not anchored with `^un`"},
+		{LintWarning, 2, "un", 2, "MinValidLen 2 has no effect with the cutoff of 2 chars"},
+		{LintError, 3, "zu", 1, "unreachable, transform #0 yields the same candidate first"},
+	}, LintRules(rules))
+
+	// The word must be longer than the cutoff anyway
+	assert.Equal([]LintIssue{
+		{LintWarning, 0, "ies", 0, "MinValidLen 4 has no effect with the cutoff of 3 chars"},
+	}, LintRules([]Rule{{
+		Affix: "ies",
+		Transforms: []RuleTransform{
+			{Cutoff: 3, ReBefore: regexp.MustCompile(`.ies$`), Augment: "y", MinValidLen: 4},
+			{Cutoff: 3, Augment: "y", MinValidLen: 5},
+		},
+	}}))
+
+	assert.Empty(LintRules(nil))
+}
+
+func TestLintIssueString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`error: rule #1 "er" transform #2: unreachable`,
+		LintIssue{Severity: LintError, Rule: 1, Affix: "er", Transform: 2, Msg: "unreachable"}.String())
+	assert.Equal(`warning: rule #0 "s": misordered`,
+		LintIssue{Rule: 0, Affix: "s", Transform: -1, Msg: "misordered"}.String())
+}
@@ -0,0 +1,72 @@
+package lm
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// NewNormalizer chains the normalization steps into a single Normalizer.
+// The steps are applied in the given order.
+func NewNormalizer(steps ...Normalizer) Normalizer {
+	return func(word string) string {
+		for _, step := range steps {
+			word = step(word)
+		}
+		return word
+	}
+}
+
+// DefaultNormalizer maps the input word to the form of the lemma index keys,
+// e.g. "‘Hood" -> "'hood", "Ｒｕｎ" -> "run".
+var DefaultNormalizer = NewNormalizer(FoldWidth, ComposeNFC, UnifyApostrophes, UnifyDashes, FoldCase)
+
+// FoldCase maps the word to lower case
+func FoldCase(word string) string {
+	return strings.ToLower(word)
+}
+
+// ComposeNFC converts the word to the Unicode Normalization Form C, so that
+// e.g. the decomposed "e" + U+0301 becomes "é".
+func ComposeNFC(word string) string {
+	return norm.NFC.String(word)
+}
+
+// FoldWidth maps the full-width and half-width characters to their canonical
+// width, e.g. "ｗｏｒｄ" -> "word".
+func FoldWidth(word string) string {
+	return width.Fold.String(word)
+}
+
+var apostropheReplacer = strings.NewReplacer(
+	"’", "'", // right single quotation mark
+	"‘", "'", // left single quotation mark
+	"ʼ", "'", // modifier letter apostrophe
+	"′", "'", // prime
+	"´", "'", // acute accent
+	"`", "'",
+)
+
+// UnifyApostrophes replaces the typographic apostrophe variants with the ASCII
+// apostrophe
+func UnifyApostrophes(word string) string {
+	return apostropheReplacer.Replace(word)
+}
+
+var dashReplacer = strings.NewReplacer(
+	"‐", "-", // hyphen
+	"‑", "-", // non-breaking hyphen
+	"‒", "-", // figure dash
+	"–", "-", // en dash
+	"—", "-", // em dash
+	"―", "-", // horizontal bar
+	"−", "-", // minus sign
+	"﹣", "-", // small hyphen-minus
+)
+
+// UnifyDashes replaces the hyphen and dash variants with the ASCII
+// hyphen-minus
+func UnifyDashes(word string) string {
+	return dashReplacer.Replace(word)
+}
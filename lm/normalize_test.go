@@ -0,0 +1,72 @@
+package lm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestNormalizers(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		n   Normalizer
+		in  string
+		out string
+		msg string
+	}{
+		{n: FoldCase, in: "Running", out: "running"},
+		{n: FoldCase, in: "ÉCOLE", out: "école"},
+		{n: ComposeNFC, in: "cafe\u0301", out: "café", msg: "Expect decomposed accent composed"},
+		{n: ComposeNFC, in: "café", out: "café"},
+		{n: FoldWidth, in: "ｗｏｒｄ", out: "word"},
+		{n: FoldWidth, in: "Ａ－ｂｏｍｂ", out: "A-bomb"},
+		{n: UnifyApostrophes, in: "‘hood", out: "'hood"},
+		{n: UnifyApostrophes, in: "a’man", out: "a'man"},
+		{n: UnifyApostrophes, in: "rock`n`roll", out: "rock'n'roll"},
+		{n: UnifyDashes, in: "a‐bomb", out: "a-bomb"},
+		{n: UnifyDashes, in: "a–line", out: "a-line"},
+		{n: DefaultNormalizer, in: "‘Hood", out: "'hood"},
+		{n: DefaultNormalizer, in: "Ｒｕｎｎｉｎｇ", out: "running"},
+		{n: DefaultNormalizer, in: "Cafe\u0301—Bar", out: "café-bar"},
+		{n: NewNormalizer(), in: "Run", out: "Run", msg: "Expect no steps keep the word"},
+		{n: NewNormalizer(FoldCase, UnifyDashes), in: "A–Line", out: "a-line"},
+	}
+
+	for _, tt := range cases {
+		assert.Equal(tt.out, tt.n(tt.in), tt.msg)
+	}
+}
+
+func TestLemmatizerNormalization(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"'hood":  {nlpgo.PosIdNoun},
+		"run":    {nlpgo.PosIdVerb},
+		"café":   {nlpgo.PosIdNoun},
+		"a-line": {nlpgo.PosIdNoun},
+	})
+	rules := []Rule{
+		{
+			Affix:      "ning",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbg},
+			Transforms: []RuleTransform{{Cutoff: 4}},
+		},
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{NewSuffixRuleResolver(rules, lmIdx)},
+		WithNormalizer(DefaultNormalizer))
+
+	for _, in := range []string{"‘hood", "’Hood", "Running", "ＲＵＮＮＩＮＧ", "Cafe\u0301", "A–line"} {
+		cc := l.Candidates(in, 0)
+		if assert.Len(cc, 1, in) {
+			assert.Equal(in, cc[0].Surface, "Expect the surface form kept")
+			assert.Equal(l.Normalize(in) == cc[0].Val, cc[0].Source == SourceLemma)
+		}
+	}
+
+	assert.Equal("run", l.Lemmatize("Running").Val)
+	assert.Equal("running", l.Normalize("Running"))
+	assert.Equal("Running", NewLemmatizer(lmIdx, nil).Normalize("Running"))
+}
@@ -0,0 +1,90 @@
+package lm
+
+// LmOption defines a functional option type for the Lemmatizer
+type LmOption func(*Lemmatizer)
+
+// Normalizer transforms an input word before it is lemmatized
+type Normalizer func(word string) string
+
+// WithMaxCandidates sets the number of candidates returned if the max
+// argument is not positive. Values < 1 are ignored.
+func WithMaxCandidates(max int) LmOption {
+	return func(l *Lemmatizer) {
+		if max > 0 {
+			l.max = max
+		}
+	}
+}
+
+// WithNormalizer sets the function applied to every input word before the
+// lookup, e.g. DefaultNormalizer or a custom NewNormalizer chain. The original
+// input is kept in Candidate.Surface.
+func WithNormalizer(n Normalizer) LmOption {
+	return func(l *Lemmatizer) {
+		l.norm = n
+	}
+}
+
+// WithRestoreCase sets whether the lemma values get the casing pattern of the
+// input word (see RestoreCase), e.g. "Running" -> "Run" if the normalizer
+// folds the case for the lookup. Disabled by default.
+func WithRestoreCase(restore bool) LmOption {
+	return func(l *Lemmatizer) {
+		l.restoreCase = restore
+	}
+}
+
+// WithCache makes the Lemmatizer cache up to size results, evicting the least
+// recently used ones. As word frequencies follow Zipf's law, a few thousand
+// entries cover most of the tokens of a typical text. See also CacheStats and
+// PurgeCache. Cache hits are not reported to the tracer set with WithTracer.
+// Values < 1 disable caching.
+func WithCache(size int) LmOption {
+	return func(l *Lemmatizer) {
+		l.cache = nil
+		if size > 0 {
+			l.cache = newResultCache(size)
+		}
+	}
+}
+
+// WithTracer makes the Lemmatizer report the steps of every call to t.
+// The Lemmatizer may call t concurrently if it is used by multiple goroutines.
+func WithTracer(t Tracer) LmOption {
+	return func(l *Lemmatizer) {
+		l.tracer = t
+	}
+}
+
+// WithResolveLemmas sets whether the resolvers are applied if the input word
+// is a lemma already (true by default). With false a lemma input word is the
+// only candidate returned, e.g. "leaves" -> "leaves" only.
+func WithResolveLemmas(resolve bool) LmOption {
+	return func(l *Lemmatizer) {
+		l.resolveLemmas = resolve
+	}
+}
+
+// WithTieBreaker sets the order of the candidates sharing the same rank,
+// e.g. the alternative lemmata of an exception entry. By default tied
+// candidates keep the order they were added in.
+func WithTieBreaker(tb TieBreaker) LmOption {
+	return func(l *Lemmatizer) {
+		l.tie = tb
+	}
+}
+
+// WithRanking sets the candidate ranking strategy, e.g. RankByScore. By
+// default the candidates are ranked in the order they are resolved (see
+// LemmaCandidates). Note the resolvers stop at max candidates, so ranking
+// reorders the resolved candidates only.
+func WithRanking(r RankFunc) LmOption {
+	return func(l *Lemmatizer) {
+		l.ranking = r
+	}
+}
+
+// RankByScore is a RankFunc ranking the candidates by Score, highest first.
+func RankByScore(a, b Candidate) bool {
+	return a.Score > b.Score
+}
@@ -0,0 +1,184 @@
+package lm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func getOptionsLemmatizer(opts ...LmOption) *Lemmatizer {
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"leaves": {nlpgo.PosIdNoun},
+		"leaf":   {nlpgo.PosIdNoun},
+		"leave":  {nlpgo.PosIdVerb, nlpgo.PosIdNoun},
+	})
+	excpIdx := map[string][]Lemma{
+		"leaves": {
+			{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+			{Val: "leaf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+		},
+		"lves": {
+			{Val: "lf", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "lv", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "lvs", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "lvv", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "lvvv", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			{Val: "lvvvv", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+		},
+	}
+
+	return NewLemmatizer(lmIdx, []LmResolver{NewExceptionResolver(excpIdx)}, opts...)
+}
+
+func vals(cc []Candidate) (vv []string) {
+	for _, c := range cc {
+		vv = append(vv, c.Val)
+	}
+	return
+}
+
+func TestWithMaxCandidates(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Len(getOptionsLemmatizer().Candidates("lves", 0), DefaultMaxCandidates)
+	assert.Len(getOptionsLemmatizer(WithMaxCandidates(2)).Candidates("lves", 0), 2)
+	assert.Len(getOptionsLemmatizer(WithMaxCandidates(2)).Candidates("lves", 3), 3,
+		"Expect explicit max overrides the option")
+	assert.Len(getOptionsLemmatizer(WithMaxCandidates(-1)).Candidates("lves", 0), DefaultMaxCandidates,
+		"Expect invalid option value ignored")
+}
+
+func TestWithNormalizer(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(getOptionsLemmatizer().Candidates(" Leaves", 0))
+
+	l := getOptionsLemmatizer(WithNormalizer(func(w string) string {
+		return strings.ToLower(strings.TrimSpace(w))
+	}))
+	assert.Equal([]string{"leaves", "leave", "leaf"}, vals(l.Candidates(" Leaves", 0)))
+	assert.Equal(Lemma{Val: "leave", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}, l.LemmatizeAs("LEAVES", nlpgo.PosIdVerb))
+
+	cc, steps := l.Explain("LEAVES", 0)
+	assert.Equal([]string{"leaves", "leave", "leaf"}, vals(cc))
+	assert.Equal("leaves", steps[0].Word)
+}
+
+func TestWithCache(t *testing.T) {
+	assert := assert.New(t)
+
+	var lookups int
+	lc := LmCheckerFunc(func(text string) Lemma {
+		lookups++
+		if text == "leaves" {
+			return Lemma{Val: text, Pos: []nlpgo.POSId{nlpgo.PosIdNoun}}
+		}
+		return Lemma{}
+	})
+	l := NewLemmatizer(lc, nil, WithCache(2))
+
+	expected := []Candidate{{
+		Lemma:        Lemma{Val: "leaves", Pos: []nlpgo.POSId{nlpgo.PosIdNoun}},
+		Surface:      "leaves",
+		Source:       SourceLemma,
+		Resolver:     -1,
+		Transform:    -1,
+		InputIsLemma: true,
+		Score:        ScoreLemma,
+	}}
+	cc := l.Candidates("leaves", 0)
+	assert.Equal(expected, cc)
+	assert.Equal(1, lookups)
+
+	// Mutating the result must not affect the cache
+	cc[0].Pos[0] = nlpgo.PosIdVerb
+	assert.Equal(expected, l.Candidates("leaves", 0))
+	assert.Equal(1, lookups, "Expect cache hit")
+
+	assert.Equal([]string{"leaves"}, vals(l.Candidates("leaves", 1)))
+	assert.Equal(2, lookups, "Expect cache miss for another max")
+	assert.Empty(l.CandidatesFor("leaves", []nlpgo.POSId{nlpgo.PosIdVerb}, 1))
+	assert.Equal(3, lookups, "Expect cache miss for another POS constraint")
+
+	// The least recently used result is evicted
+	l.Candidates("leaves", 0)
+	assert.Equal(4, lookups)
+
+	l = NewLemmatizer(lc, nil, WithCache(0))
+	l.Candidates("leaves", 0)
+	l.Candidates("leaves", 0)
+	assert.Equal(6, lookups, "Expect no caching")
+}
+
+func TestWithTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	var steps []TraceStep
+	l := getOptionsLemmatizer(WithTracer(TracerFunc(func(s TraceStep) {
+		steps = append(steps, s)
+	})))
+
+	l.Lemmatize("leaves")
+	assert.Equal([]StepKind{StepLookup, StepChoice}, []StepKind{steps[0].Kind, steps[1].Kind})
+
+	steps = nil
+	l.Candidates("leaves", 0)
+	assert.Len(steps, 6)
+}
+
+func TestWithResolveLemmas(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"leaves", "leave", "leaf"}, vals(getOptionsLemmatizer().Candidates("leaves", 0)))
+	assert.Equal([]string{"leaves", "leave", "leaf"},
+		vals(getOptionsLemmatizer(WithResolveLemmas(true)).Candidates("leaves", 0)))
+	assert.Equal([]string{"leaves"},
+		vals(getOptionsLemmatizer(WithResolveLemmas(false)).Candidates("leaves", 0)))
+	assert.Equal([]string{"leave"},
+		vals(getOptionsLemmatizer(WithResolveLemmas(false)).CandidatesFor("leaves", []nlpgo.POSId{nlpgo.PosIdVerb}, 0)),
+		"Expect resolvers applied if the input lemma POS is not allowed")
+}
+
+func TestWithRanking(t *testing.T) {
+	assert := assert.New(t)
+
+	byLen := func(a, b Candidate) bool { return len(a.Val) < len(b.Val) }
+
+	assert.Equal([]string{"leaves", "leave", "leaf"}, vals(getOptionsLemmatizer().Candidates("leaves", 0)))
+	assert.Equal([]string{"leaf", "leave", "leaves"},
+		vals(getOptionsLemmatizer(WithRanking(byLen)).Candidates("leaves", 0)))
+	assert.Equal([]string{"lf", "lv", "lvs"},
+		vals(getOptionsLemmatizer(WithRanking(byLen)).Candidates("lves", 3)))
+
+	// The input is a lemma wherever the ranking puts it
+	cc := getOptionsLemmatizer(WithRanking(byLen)).Candidates("leaves", 0)
+	assert.Equal(SourceLemma, cc[2].Source)
+	for _, c := range cc {
+		assert.True(c.InputIsLemma, c.Val)
+	}
+
+	lc := LmCheckerFunc(func(text string) Lemma { return Lemma{} })
+	l := NewLemmatizer(lc, []LmResolver{
+		resolverFunc(func(word string, acc *LemmaAccumulator, max int) {
+			acc.Add(Candidate{Lemma: Lemma{Val: "low"}, Score: 0.1})
+			acc.Add(Candidate{Lemma: Lemma{Val: "high"}, Score: 0.9})
+			acc.Add(Candidate{Lemma: Lemma{Val: "mid"}, Score: 0.5})
+		}),
+	}, WithRanking(RankByScore))
+	assert.Equal([]string{"high", "mid", "low"}, vals(l.Candidates("any", 0)))
+}
+
+type resolverFunc func(word string, acc *LemmaAccumulator, max int)
+
+func (f resolverFunc) Resolve(word string, acc *LemmaAccumulator, max int) {
+	f(word, acc, max)
+}
+
+func TestWithTieBreaker(t *testing.T) {
+	assert := assert.New(t)
+
+	l := getOptionsLemmatizer(WithTieBreaker(func(a, b Lemma) bool { return a.Val > b.Val }))
+	assert.Equal([]string{"lvvv", "lvv", "lvs", "lv", "lf"}, vals(l.Candidates("lves", 0)))
+}
@@ -0,0 +1,42 @@
+package lm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RestoreCase maps the casing pattern of the surface word onto the lemma:
+//	- all caps surface yields all caps lemma: "RUNNING" -> "RUN"
+//	- otherwise the case is copied rune by rune: "Running" -> "Run",
+//	  "iPhones" -> "iPhone", "NASAs" -> "NASA"
+// The lemma runes beyond the surface length keep their case.
+func RestoreCase(surface, lemma string) string {
+	var upper, lower int
+	for _, r := range surface {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return lemma
+	case lower == 0 && upper > 1:
+		return strings.ToUpper(lemma)
+	}
+
+	sr := []rune(surface)
+	lr := []rune(lemma)
+	for i := range lr {
+		if i >= len(sr) {
+			break
+		}
+		if unicode.IsUpper(sr[i]) {
+			lr[i] = unicode.ToUpper(lr[i])
+		}
+	}
+
+	return string(lr)
+}
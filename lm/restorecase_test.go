@@ -0,0 +1,75 @@
+package lm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestRestoreCase(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		surface string
+		lemma   string
+		out     string
+	}{
+		{surface: "running", lemma: "run", out: "run"},
+		{surface: "Running", lemma: "run", out: "Run"},
+		{surface: "RUNNING", lemma: "run", out: "RUN"},
+		{surface: "iPhones", lemma: "iphone", out: "iPhone"},
+		{surface: "NASAs", lemma: "nasa", out: "NASA"},
+		{surface: "MICE", lemma: "mouse", out: "MOUSE"},
+		{surface: "Mice", lemma: "mouse", out: "Mouse"},
+		{surface: "Went", lemma: "go", out: "Go"},
+		{surface: "I", lemma: "i", out: "I"},
+		{surface: "‘Hood", lemma: "'hood", out: "'Hood"},
+		{surface: "ÉCOLES", lemma: "école", out: "ÉCOLE"},
+		{surface: "X-RAYS", lemma: "x-ray", out: "X-RAY"},
+		{surface: "123", lemma: "123", out: "123"},
+	}
+
+	for _, tt := range cases {
+		assert.Equal(tt.out, RestoreCase(tt.surface, tt.lemma), tt.surface)
+	}
+}
+
+func TestWithRestoreCase(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"run":    {nlpgo.PosIdVerb},
+		"iphone": {nlpgo.PosIdNoun},
+	})
+	rules := []Rule{
+		{
+			Affix:      "ning",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbg},
+			Transforms: []RuleTransform{{Cutoff: 4}},
+		},
+		{
+			Affix:      "s",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{{Cutoff: 1}},
+		},
+	}
+	rs := []LmResolver{NewSuffixRuleResolver(rules, lmIdx)}
+
+	l := NewLemmatizer(lmIdx, rs, WithNormalizer(FoldCase), WithRestoreCase(true), WithCache(10))
+	for _, tt := range [][]string{
+		{"Running", "Run"},
+		{"RUNNING", "RUN"},
+		{"running", "run"},
+		{"iPhones", "iPhone"},
+	} {
+		assert.Equal(tt[1], l.Lemmatize(tt[0]).Val)
+	}
+
+	cc, _ := l.Explain("Running", 0)
+	assert.Equal("Run", cc[0].Val)
+	assert.Equal("Running", cc[0].Surface)
+
+	l = NewLemmatizer(lmIdx, rs, WithNormalizer(FoldCase))
+	assert.Equal("run", l.Lemmatize("Running").Val, "Expect lowercase lemma by default")
+}
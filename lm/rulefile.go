@@ -0,0 +1,366 @@
+package lm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/timurgarif/nlpgo"
+)
+
+// RuleSyntaxError reports an invalid rule file line
+type RuleSyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *RuleSyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// LoadRules reads the rules from r in the rule file format.
+// An error is returned if the input is invalid, it's a *RuleSyntaxError for
+// the format errors.
+//
+// The rule file format is line based. A `rule` line starts a Rule, the
+// following `transform` lines add RuleTransform items to it. Empty lines and
+// the text after `#` are ignored. For example:
+//
+//	# Kind, affix and the POS forms (names or numeric ids)
+//	rule suffix ing pos=VBG
+//		transform cutoff=3 augment=e min-len=5
+//		transform cutoff=4 min-len=6 before=`.[aeiou](bb|dd)ing$`
+//	rule circumfix ge end=t pos=VBN
+//		transform head-cutoff=2 cutoff=1 augment=en
+//	rule suffix feet pos=NNS
+//		transform subst=`feet$` replace=foot
+//
+// The rule kinds are suffix, prefix, circumfix and infix. The transform keys
+// are cutoff, augment, head-cutoff, head-augment, min-len, before, after,
+// subst and replace, see RuleTransform for their meaning.
+// Values containing spaces, quotes, `=` or `#` must be quoted as Go string
+// literals, either "interpreted" or `raw`.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	scanner := bufio.NewScanner(r)
+	ln := 0
+	for scanner.Scan() {
+		ln++
+
+		toks, err := splitRuleLine(scanner.Text())
+		if err != nil {
+			return nil, &RuleSyntaxError{Line: ln, Msg: err.Error()}
+		}
+		if len(toks) == 0 {
+			continue
+		}
+
+		switch toks[0].key {
+		case "rule":
+			rule, err := parseRule(toks[1:])
+			if err != nil {
+				return nil, &RuleSyntaxError{Line: ln, Msg: err.Error()}
+			}
+			rules = append(rules, rule)
+		case "transform":
+			if len(rules) == 0 {
+				return nil, &RuleSyntaxError{Line: ln, Msg: "transform before any rule"}
+			}
+			rt, err := parseTransform(toks[1:])
+			if err != nil {
+				return nil, &RuleSyntaxError{Line: ln, Msg: err.Error()}
+			}
+			last := &rules[len(rules)-1]
+			last.Transforms = append(last.Transforms, rt)
+		default:
+			return nil, &RuleSyntaxError{Line: ln, Msg: fmt.Sprintf("unknown directive %q", toks[0].key)}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ruleToken is either a bare value (key is the value) or a key=value pair
+type ruleToken struct {
+	key  string
+	val  string
+	isKV bool
+}
+
+func splitRuleLine(line string) (toks []ruleToken, err error) {
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= len(line) || line[i] == '#' {
+			return
+		}
+
+		var tok ruleToken
+
+		// A quoted bare value
+		if line[i] == '"' || line[i] == '`' {
+			tok.key, i, err = scanRuleValue(line, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, tok)
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '=' {
+			if line[i] == '"' || line[i] == '`' || line[i] == '#' {
+				return nil, fmt.Errorf("unexpected %q in %q", line[i], line[start:i])
+			}
+			i++
+		}
+		tok.key = line[start:i]
+
+		if i < len(line) && line[i] == '=' {
+			i++
+			tok.isKV = true
+			if tok.key == "" {
+				return nil, fmt.Errorf("missing key before '='")
+			}
+			tok.val, i, err = scanRuleValue(line, i)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", tok.key, err)
+			}
+		}
+
+		toks = append(toks, tok)
+	}
+}
+
+// scanRuleValue scans a plain or quoted value starting at i and returns it
+// with the index after it
+func scanRuleValue(line string, i int) (string, int, error) {
+	if i < len(line) && (line[i] == '"' || line[i] == '`') {
+		q := line[i]
+		j := i + 1
+		for ; j < len(line); j++ {
+			if q == '"' && line[j] == '\\' {
+				j++
+				continue
+			}
+			if line[j] == q {
+				break
+			}
+		}
+		if j >= len(line) {
+			return "", j, fmt.Errorf("unterminated quoted value")
+		}
+		v, err := strconv.Unquote(line[i : j+1])
+		if err != nil {
+			return "", j, fmt.Errorf("invalid quoted value %s", line[i:j+1])
+		}
+		return v, j + 1, nil
+	}
+
+	start := i
+	for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+		if line[i] == '"' || line[i] == '`' || line[i] == '=' || line[i] == '#' {
+			return "", i, fmt.Errorf("unexpected %q in value", line[i])
+		}
+		i++
+	}
+
+	return line[start:i], i, nil
+}
+
+var affixKinds = map[string]AffixKind{
+	"suffix":    AffixSuffix,
+	"prefix":    AffixPrefix,
+	"circumfix": AffixCircumfix,
+	"infix":     AffixInfix,
+}
+
+func parseRule(toks []ruleToken) (r Rule, err error) {
+	if len(toks) < 2 || toks[0].isKV || toks[1].isKV {
+		return r, fmt.Errorf("rule kind and affix expected")
+	}
+
+	kind, ok := affixKinds[toks[0].key]
+	if !ok {
+		return r, fmt.Errorf("unknown rule kind %q", toks[0].key)
+	}
+	r.Kind = kind
+	r.Affix = toks[1].key
+
+	for _, t := range toks[2:] {
+		if !t.isKV {
+			return r, fmt.Errorf("unexpected %q, key=value expected", t.key)
+		}
+		switch t.key {
+		case "end":
+			r.EndAffix = t.val
+		case "pos":
+			if r.Pos, err = parsePosList(t.val); err != nil {
+				return
+			}
+		default:
+			return r, fmt.Errorf("unknown rule key %q", t.key)
+		}
+	}
+
+	if r.Kind == AffixCircumfix && r.EndAffix == "" {
+		return r, fmt.Errorf("circumfix rule requires end affix")
+	}
+	if r.Kind != AffixCircumfix && r.EndAffix != "" {
+		return r, fmt.Errorf("end affix is allowed for circumfix rules only")
+	}
+
+	return
+}
+
+func parsePosList(s string) (pp []nlpgo.POSId, err error) {
+	for _, v := range strings.Split(s, ",") {
+		p, err := nlpgo.ParsePOS(v)
+		if err != nil {
+			return nil, err
+		}
+		pp = append(pp, p)
+	}
+
+	return
+}
+
+func parseTransform(toks []ruleToken) (rt RuleTransform, err error) {
+	for _, t := range toks {
+		if !t.isKV {
+			return rt, fmt.Errorf("unexpected %q, key=value expected", t.key)
+		}
+
+		switch t.key {
+		case "cutoff":
+			rt.Cutoff, err = parseRuleInt(t)
+		case "head-cutoff":
+			rt.HeadCutoff, err = parseRuleInt(t)
+		case "min-len":
+			rt.MinValidLen, err = parseRuleInt(t)
+		case "augment":
+			rt.Augment = t.val
+		case "head-augment":
+			rt.HeadAugment = t.val
+		case "replace":
+			rt.Replace = t.val
+		case "before":
+			rt.ReBefore, err = parseRuleRegexp(t)
+		case "after":
+			rt.ReAfter, err = parseRuleRegexp(t)
+		case "subst":
+			rt.Subst, err = parseRuleRegexp(t)
+		default:
+			err = fmt.Errorf("unknown transform key %q", t.key)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func parseRuleInt(t ruleToken) (int, error) {
+	v, err := strconv.Atoi(t.val)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s: non-negative integer expected, got %q", t.key, t.val)
+	}
+	return v, nil
+}
+
+func parseRuleRegexp(t ruleToken) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(t.val)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", t.key, err)
+	}
+	return re, nil
+}
+
+// WriteRules writes the rules to w in the rule file format (see LoadRules)
+func WriteRules(w io.Writer, rules []Rule) error {
+	bw := bufio.NewWriter(w)
+
+	for i, r := range rules {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+
+		fmt.Fprintf(bw, "rule %s %s", r.Kind, quoteRuleValue(r.Affix))
+		if r.Kind == AffixCircumfix {
+			fmt.Fprintf(bw, " end=%s", quoteRuleValue(r.EndAffix))
+		}
+		if len(r.Pos) > 0 {
+			pp := make([]string, len(r.Pos))
+			for i, p := range r.Pos {
+				if name := p.POS(); name != "" {
+					pp[i] = string(name)
+				} else {
+					pp[i] = strconv.Itoa(int(p))
+				}
+			}
+			fmt.Fprintf(bw, " pos=%s", strings.Join(pp, ","))
+		}
+		bw.WriteString("\n")
+
+		for _, rt := range r.Transforms {
+			bw.WriteString("\ttransform")
+			writeRuleInt(bw, "head-cutoff", rt.HeadCutoff)
+			writeRuleString(bw, "head-augment", rt.HeadAugment)
+			writeRuleInt(bw, "cutoff", rt.Cutoff)
+			writeRuleString(bw, "augment", rt.Augment)
+			writeRuleInt(bw, "min-len", rt.MinValidLen)
+			writeRuleRegexp(bw, "before", rt.ReBefore)
+			writeRuleRegexp(bw, "after", rt.ReAfter)
+			writeRuleRegexp(bw, "subst", rt.Subst)
+			writeRuleString(bw, "replace", rt.Replace)
+			bw.WriteString("\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeRuleInt(w io.Writer, key string, v int) {
+	if v != 0 {
+		fmt.Fprintf(w, " %s=%d", key, v)
+	}
+}
+
+func writeRuleString(w io.Writer, key, v string) {
+	if v != "" {
+		fmt.Fprintf(w, " %s=%s", key, quoteRuleValue(v))
+	}
+}
+
+func writeRuleRegexp(w io.Writer, key string, re *regexp.Regexp) {
+	if re == nil {
+		return
+	}
+
+	v := re.String()
+	if !strings.Contains(v, "`") {
+		v = "`" + v + "`"
+	} else {
+		v = strconv.Quote(v)
+	}
+	fmt.Fprintf(w, " %s=%s", key, v)
+}
+
+// quoteRuleValue quotes the value if it can't be written as is
+func quoteRuleValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\"`=#") || !strconv.CanBackquote(v) {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -0,0 +1,141 @@
+package lm
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+const testRuleFile = `# Test rules
+rule suffix ing pos=VBG # the gerund
+	transform cutoff=3 augment=e min-len=5
+	transform cutoff=4 min-len=6 before=` + "`" + `.[aeiou](bb|dd)ing$` + "`" + `
+
+rule circumfix ge end=t pos=VBN,2
+	transform head-cutoff=2 cutoff=1 augment=en after="^\\pL+$"
+rule prefix "un" pos=ADJ
+	transform head-cutoff=2 head-augment="a b"
+rule suffix feet pos=NNS
+	transform subst=` + "`" + `feet$` + "`" + ` replace=${1}foot
+`
+
+func TestLoadRules(t *testing.T) {
+	assert := assert.New(t)
+
+	rules, err := LoadRules(strings.NewReader(testRuleFile))
+	assert.NoError(err)
+	assert.Equal([]Rule{
+		{
+			Affix: "ing",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbg},
+			Transforms: []RuleTransform{
+				{Cutoff: 3, Augment: "e", MinValidLen: 5},
+				{Cutoff: 4, MinValidLen: 6, ReBefore: regexp.MustCompile(`.[aeiou](bb|dd)ing$`)},
+			},
+		},
+		{
+			Kind:     AffixCircumfix,
+			Affix:    "ge",
+			EndAffix: "t",
+			Pos:      []nlpgo.POSId{nlpgo.PosIdVbn, nlpgo.PosIdNoun},
+			Transforms: []RuleTransform{
+				{HeadCutoff: 2, Cutoff: 1, Augment: "en", ReAfter: regexp.MustCompile(`^\pL+$`)},
+			},
+		},
+		{
+			Kind:       AffixPrefix,
+			Affix:      "un",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdAdj},
+			Transforms: []RuleTransform{{HeadCutoff: 2, HeadAugment: "a b"}},
+		},
+		{
+			Affix:      "feet",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{{Subst: regexp.MustCompile(`feet$`), Replace: "${1}foot"}},
+		},
+	}, rules)
+
+	rules, err = LoadRules(strings.NewReader(""))
+	assert.NoError(err)
+	assert.Empty(rules)
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		in  string
+		err string
+	}{
+		{in: "transform cutoff=1", err: "line 1: transform before any rule"},
+		{in: "rule suffix s\n\n  foo", err: `line 3: unknown directive "foo"`},
+		{in: "rule suffix", err: "line 1: rule kind and affix expected"},
+		{in: "rule midfix s", err: `line 1: unknown rule kind "midfix"`},
+		{in: "rule suffix s pos=XX", err: `line 1: invalid POS "XX"`},
+		{in: "rule suffix s pos=NNS,", err: `line 1: invalid POS ""`},
+		{in: "rule suffix s foo=1", err: `line 1: unknown rule key "foo"`},
+		{in: "rule suffix s NNS", err: `line 1: unexpected "NNS", key=value expected`},
+		{in: "rule circumfix ge", err: "line 1: circumfix rule requires end affix"},
+		{in: "rule suffix s end=t", err: "line 1: end affix is allowed for circumfix rules only"},
+		{in: "rule suffix s\n transform cutoff=x", err: `line 2: cutoff: non-negative integer expected, got "x"`},
+		{in: "rule suffix s\n transform min-len=-1", err: `line 2: min-len: non-negative integer expected, got "-1"`},
+		{in: "rule suffix s\n transform before=`(`", err: "line 2: before: error parsing regexp: missing closing ): `(`"},
+		{in: "rule suffix s\n transform augment=\"e", err: "line 2: augment: unterminated quoted value"},
+		{in: "rule suffix s\n transform augment=\"\\q\"", err: `line 2: augment: invalid quoted value "\q"`},
+		{in: "rule suffix s\n transform augment=a=b", err: `line 2: augment: unexpected '=' in value`},
+		{in: "rule suffix s\n transform =a", err: "line 2: missing key before '='"},
+		{in: "rule suffix s\n transform cut\"off=1", err: `line 2: unexpected '"' in "cut"`},
+		{in: "rule suffix s\n transform foo=1", err: `line 2: unknown transform key "foo"`},
+	}
+
+	for _, tt := range cases {
+		_, err := LoadRules(strings.NewReader(tt.in))
+		if assert.Error(err, tt.in) {
+			assert.Equal(tt.err, err.Error())
+			_, ok := err.(*RuleSyntaxError)
+			assert.True(ok)
+		}
+	}
+}
+
+func TestWriteRules(t *testing.T) {
+	assert := assert.New(t)
+
+	rules, err := LoadRules(strings.NewReader(testRuleFile))
+	assert.NoError(err)
+	rules = append(rules, Rule{
+		Kind:  AffixInfix,
+		Affix: "a#b",
+		Pos:   []nlpgo.POSId{99},
+		Transforms: []RuleTransform{
+			{Augment: "", ReBefore: regexp.MustCompile("a`b")},
+		},
+	})
+
+	var b bytes.Buffer
+	assert.NoError(WriteRules(&b, rules))
+	assert.Equal(`rule suffix ing pos=VBG
+	transform cutoff=3 augment=e min-len=5
+	transform cutoff=4 min-len=6 before=`+"`.[aeiou](bb|dd)ing$`"+`
+
+rule circumfix ge end=t pos=VBN,NOUN
+	transform head-cutoff=2 cutoff=1 augment=en after=`+"`^\\pL+$`"+`
+
+rule prefix un pos=ADJ
+	transform head-cutoff=2 head-augment="a b"
+
+rule suffix feet pos=NNS
+	transform subst=`+"`feet$`"+` replace=${1}foot
+
+rule infix "a#b" pos=99
+	transform before="a`+"`"+`b"
+`, b.String())
+
+	loaded, err := LoadRules(&b)
+	assert.NoError(err)
+	assert.Equal(rules, loaded, "Expect written rules loaded back")
+}
@@ -2,25 +2,73 @@ package lm
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/timurgarif/nlpgo"
 )
 
+// AffixKind defines the position of a Rule affix in a word
+type AffixKind uint8
+
+const (
+	// The affix ends the word, e.g. English "-ing"
+	AffixSuffix AffixKind = iota
+	// The affix starts the word, e.g. English "un-" or Russian "по-"
+	AffixPrefix
+	// The affix surrounds the word stem, e.g. German "ge-...-t"
+	AffixCircumfix
+	// The affix is inside the word, e.g. German "auf-zu-machen"
+	AffixInfix
+)
+
+var affixKindNames = [...]string{
+	AffixSuffix:    "suffix",
+	AffixPrefix:    "prefix",
+	AffixCircumfix: "circumfix",
+	AffixInfix:     "infix",
+}
+
+func (k AffixKind) String() string {
+	if int(k) < len(affixKindNames) {
+		return affixKindNames[k]
+	}
+	return "unknown"
+}
+
 // Rule defines an affix-related attributes to restore a lemma from a word.
-// Currently only suffix-based rules are supported.
+// The affix is a suffix by default, see Kind.
 type Rule struct {
+	// Kind of the affix (suffix if zero value)
+	Kind AffixKind
+	// The affix. For circumfix rules it's the leading part of the affix.
 	Affix string
-	// Tag(s) to identify the inflection form
+	// The trailing part of the affix (circumfix rules only)
+	EndAffix string
+	// Tag(s) to identify the inflection form. A base POS tag (e.g.
+	// nlpgo.PosIdAdj) matches the lemmata of the same POS, which suits the
+	// affixes keeping the POS like "un-" in "unhappy".
 	Pos        []nlpgo.POSId
 	Transforms []RuleTransform
 }
 
+// RuleTransform defines how to get a lemma candidate from the word matched by
+// the Rule affix. The word is trimmed by HeadCutoff and Cutoff chars, then
+// HeadAugment and Augment are attached. For infix rules the affix itself is
+// replaced by Augment while Cutoff and HeadCutoff are ignored.
+// If Subst is set, the first Subst match is replaced by the Replace template
+// instead, which allows the internal stem changes like "feet" -> "foot".
 type RuleTransform struct {
 	// Number of chars to discard
 	Cutoff int
 	// A compensative affix to augment to a word after Affix detaching
 	// [optional]
 	Augment string
+	// Number of chars to discard from the word start, e.g. for prefix rules
+	// [optional]
+	HeadCutoff int
+	// A compensative affix to prepend to a word after HeadCutoff
+	// [optional]
+	HeadAugment string
 	// Minimal word length used as a threshold trigger to apply the rule.
 	// [optional] (ignored if zero value).
 	MinValidLen int
@@ -30,20 +78,61 @@ type RuleTransform struct {
 	// A regexp to validate the lemma candidate after Affix detaching
 	// [optional]
 	ReAfter *regexp.Regexp
+	// A regexp to substitute in the word, e.g. `([lm])ice$` for "mice".
+	// The Cutoff and Augment fields are ignored if set.
+	// [optional]
+	Subst *regexp.Regexp
+	// A replacement template for the Subst match, may refer to its capture
+	// groups like in regexp.Regexp.Expand, e.g. "${1}ouse" for "mice".
+	// [optional]
+	Replace string
 }
 
 type ruleResolver struct {
-	rs []Rule
-	lc LmChecker
+	rs  []Rule
+	lc  LmChecker
+	all bool
+	// Whether to apply rules of any AffixKind, not suffix ones only
+	anyKind bool
+}
+
+// RuleOption defines a functional option type for the rule resolvers
+type RuleOption func(*ruleResolver)
+
+// WithAllMatches makes the resolver apply every Rule and every RuleTransform
+// and add all the confirmed lemma candidates (up to max), e.g. "axes" ->
+// "axe", "ax". By default the resolver stops at the first confirmed
+// candidate.
+func WithAllMatches() RuleOption {
+	return func(rr *ruleResolver) {
+		rr.all = true
+	}
 }
 
-func NewSuffixRuleResolver(rules []Rule, lc LmChecker) LmResolver {
-	return &ruleResolver{rs: rules, lc: lc}
+// NewSuffixRuleResolver creates a resolver applying the suffix rules. The
+// rules of other kinds are skipped.
+func NewSuffixRuleResolver(rules []Rule, lc LmChecker, opts ...RuleOption) LmResolver {
+	rr := &ruleResolver{rs: rules, lc: lc}
+	for _, opt := range opts {
+		opt(rr)
+	}
+
+	return rr
+}
+
+// NewAffixRuleResolver creates a resolver applying the rules of any AffixKind
+// in the given order.
+func NewAffixRuleResolver(rules []Rule, lc LmChecker, opts ...RuleOption) LmResolver {
+	rr := &ruleResolver{rs: rules, lc: lc, anyKind: true}
+	for _, opt := range opts {
+		opt(rr)
+	}
+
+	return rr
 }
 
-func (rr *ruleResolver) Resolve(word string, acc LemmaAccumulator, max int) {
+func (rr *ruleResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
 	wdRuneLen := len([]rune(word))
-	wdLen := len(word)
 
 	// If no rules are specified yield no lemma candidates
 	if rr.rs == nil {
@@ -52,74 +141,225 @@ func (rr *ruleResolver) Resolve(word string, acc LemmaAccumulator, max int) {
 
 	// If no lemma cheker, consider the word is lemma
 	if rr.lc == nil {
-		acc.Set(word, nil)
+		if _, ok := acc.FilterPos(nil); ok {
+			acc.Set(word, nil)
+		}
 		return
 	}
 
-	for _, r := range rr.rs {
-		// Match the word ending to suffix
-		sfxStartIndex := wdLen - len(r.Affix)
-		if sfxStartIndex <= 0 ||
-			r.Affix != word[sfxStartIndex:] {
+	for i := range rr.rs {
+		r := &rr.rs[i]
+		if r.Kind != AffixSuffix && !rr.anyKind {
 			continue
 		}
 
-		// Apply transforms until successful match or end
-		for _, rt := range r.Transforms {
-			c := rt.transform(word, wdRuneLen)
+		at := r.match(word)
+		if at < 0 {
+			continue
+		}
 
-			if c == "" {
-				continue
-			}
+		if rr.apply(word, wdRuneLen, r, at, nil, acc, max) {
+			return
+		}
+	}
+}
 
-			l := rr.lc.lookup(c)
-			if l.Val == "" {
-				continue
-			}
+// apply applies the transforms of the rule matched at the `at` byte index and
+// adds the confirmed lemma candidates. The before funcs, if any, replace the
+// ReBefore regexps of the transforms. It returns true if the resolver must
+// stop.
+func (rr *ruleResolver) apply(word string, wdRuneLen int, r *Rule, at int, before []func(string) bool,
+	acc *LemmaAccumulator, max int) bool {
+	// Skip the rule if none of its forms is allowed
+	rpp, ok := acc.FilterPos(r.Pos)
+	if acc.Tracing() {
+		st := TraceStep{Kind: StepRule, Word: word, Affix: r.Affix, Transform: -1, Pos: r.Pos}
+		if !ok {
+			st.Reject = RejectPos
+		}
+		acc.Trace(st)
+	}
+	if !ok {
+		return false
+	}
+
+	// Apply transforms until successful match or end
+	for ti := range r.Transforms {
+		rt := &r.Transforms[ti]
 
-			// Match lemma candidate POS'es to the rule form POS'es.
-			var pp []nlpgo.POSId
+		var bf func(string) bool
+		if before != nil {
+			bf = before[ti]
+		}
+		c, rej := rt.transform(word, wdRuneLen, r, at, bf)
+
+		var l Lemma
+		if rej == RejectNone {
+			l, rej = lookupFor(rr.lc, c, acc)
+		}
+
+		// Match lemma candidate POS'es to the rule form POS'es.
+		var pp []nlpgo.POSId
+		if rej == RejectNone {
 			for _, lemmaPos := range l.Pos {
-				for _, rulePos := range r.Pos {
-					if lemmaPos.HasForm(rulePos) {
+				for _, rulePos := range rpp {
+					if lemmaPos == rulePos || lemmaPos.HasForm(rulePos) {
 						pp = append(pp, rulePos)
 					}
 				}
 			}
-			// If any rule POS forms correspond to the cheker lemma POS'es
-			// then a proper word -> lemma match found
-			if len(pp) > 0 {
-				acc.Set(l.Val, pp)
-
-				return
-				// TODO: Currently we stop after the first rule match
-				// 	Consider to parameterize the logic to allow all the rules to be applied
-				// in order to look for more matches.
+			if len(pp) == 0 {
+				rej = RejectPos
 			}
 		}
+
+		if acc.Tracing() {
+			st := TraceStep{Kind: StepTransform, Word: word, Affix: r.Affix, Transform: ti, Candidate: c, Pos: pp, Reject: rej}
+			if rej == RejectPos {
+				st.Pos = l.Pos
+			}
+			acc.Trace(st)
+		}
+		if rej != RejectNone {
+			continue
+		}
+
+		// If any rule POS forms correspond to the cheker lemma POS'es
+		// then a proper word -> lemma match found
+		acc.Add(Candidate{
+			Lemma:     Lemma{Val: l.Val, Pos: pp},
+			Source:    SourceRule,
+			Affix:     r.Affix,
+			Transform: ti,
+			Score:     rt.score(),
+		})
+
+		if !rr.all || acc.Len() >= max {
+			return true
+		}
 	}
+
+	return false
 }
 
-func (rt *RuleTransform) transform(word string, wdRuneLen int) string {
-	if wdRuneLen < rt.MinValidLen {
-		return ""
-	}
-	detachIndex := wdRuneLen - rt.Cutoff
-	if detachIndex <= 0 {
-		return ""
+// match returns the byte index of the rule affix in the word, or -1 if the
+// affix does not match. The affix must leave at least one char of the word.
+func (r *Rule) match(word string) int {
+	wdLen := len(word)
+
+	switch r.Kind {
+	case AffixSuffix:
+		// Match the word ending to suffix
+		sfxStartIndex := wdLen - len(r.Affix)
+		if sfxStartIndex <= 0 ||
+			r.Affix != word[sfxStartIndex:] {
+			return -1
+		}
+		return sfxStartIndex
+	case AffixPrefix:
+		if wdLen <= len(r.Affix) || !strings.HasPrefix(word, r.Affix) {
+			return -1
+		}
+		return 0
+	case AffixCircumfix:
+		if wdLen <= len(r.Affix)+len(r.EndAffix) ||
+			!strings.HasPrefix(word, r.Affix) ||
+			!strings.HasSuffix(word, r.EndAffix) {
+			return -1
+		}
+		return 0
+	case AffixInfix:
+		// The first occurrence which is neither prefix nor suffix
+		if r.Affix == "" || wdLen <= len(r.Affix)+1 {
+			return -1
+		}
+		i := strings.Index(word[1:wdLen-1], r.Affix)
+		if i < 0 {
+			return -1
+		}
+		return i + 1
 	}
 
-	// Apply pre-op regexp
-	if rt.ReBefore != nil && !rt.ReBefore.MatchString(word) {
-		return ""
+	return -1
+}
+
+// transform returns the lemma candidate for the word matched by the rule at
+// the `at` byte index, or the reason why the transform is not applicable. On
+// ReAfter mismatch the rejected candidate is returned as well. If before is
+// not nil, it's used instead of the ReBefore regexp.
+func (rt *RuleTransform) transform(word string, wdRuneLen int, r *Rule, at int, before func(string) bool) (string, Reject) {
+	if wdRuneLen < rt.MinValidLen {
+		return "", RejectMinValidLen
 	}
 
-	c := string([]rune(word)[:detachIndex]) + rt.Augment
+	var c string
+	switch {
+	case rt.Subst != nil:
+		// Apply pre-op regexp
+		if !rt.matchBefore(word, before) {
+			return "", RejectReBefore
+		}
+
+		loc := rt.Subst.FindStringSubmatchIndex(word)
+		if loc == nil {
+			return "", RejectSubst
+		}
+		c = word[:loc[0]] + string(rt.Subst.ExpandString(nil, rt.Replace, word, loc)) + word[loc[1]:]
+	case r.Kind == AffixInfix:
+		// Apply pre-op regexp
+		if !rt.matchBefore(word, before) {
+			return "", RejectReBefore
+		}
+
+		c = word[:at] + rt.Augment + word[at+len(r.Affix):]
+	default:
+		detachIndex := wdRuneLen - rt.Cutoff
+		if detachIndex <= rt.HeadCutoff {
+			return "", RejectCutoff
+		}
+
+		// Apply pre-op regexp
+		if !rt.matchBefore(word, before) {
+			return "", RejectReBefore
+		}
+
+		if len(word) == wdRuneLen {
+			// No multibyte chars, so the byte indexes are the char ones. The
+			// plain cutoff is a substring of the word, no copy is needed.
+			c = word[rt.HeadCutoff:detachIndex]
+			if rt.HeadAugment != "" || rt.Augment != "" {
+				c = rt.HeadAugment + c + rt.Augment
+			}
+		} else {
+			c = rt.HeadAugment + string([]rune(word)[rt.HeadCutoff:detachIndex]) + rt.Augment
+		}
+	}
 
 	// Apply post-op regexp
 	if rt.ReAfter != nil && !rt.ReAfter.MatchString(c) {
-		return ""
+		return c, RejectReAfter
+	}
+
+	return c, RejectNone
+}
+
+// matchBefore reports whether the word passes the ReBefore check, using the
+// before func instead of the regexp if set
+func (rt *RuleTransform) matchBefore(word string, before func(string) bool) bool {
+	if rt.ReBefore == nil {
+		return true
+	}
+	if before != nil {
+		return before(word)
 	}
+	return rt.ReBefore.MatchString(word)
+}
 
-	return c
+// score returns the candidate score of the transform: a transform validated
+// by a context regexp is more reliable than a context-free one.
+func (rt *RuleTransform) score() float64 {
+	if rt.ReBefore != nil || rt.ReAfter != nil || rt.Subst != nil {
+		return ScoreRuleContext
+	}
+	return ScoreRule
 }
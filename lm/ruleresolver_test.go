@@ -77,6 +77,7 @@ func TestResolve(t *testing.T) {
 	emptyResolver := NewSuffixRuleResolver(nil, nil)
 	noChkResolver := NewSuffixRuleResolver(rules, nil)
 	resolver := NewSuffixRuleResolver(rules, lmChecker)
+	allResolver := NewSuffixRuleResolver(rules, lmChecker, WithAllMatches())
 
 	cases := []struct {
 		in  string
@@ -146,6 +147,21 @@ func TestResolve(t *testing.T) {
 			out: []Lemma{{Val: "слушать", Pos: []nlpgo.POSId{nlpgo.PosIdVbd}}},
 			msg: "Expect rune words are handled correctly",
 		},
+		{
+			in: "striping",
+			out: []Lemma{
+				{Val: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+				{Val: "strip", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}},
+			},
+			msg: "Expect striping -> stripe, strip with all matches",
+			r:   allResolver,
+		},
+		{
+			in:  "stripping",
+			out: []Lemma{{Val: "strip", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}},
+			msg: "Expect stripping -> strip only with all matches",
+			r:   allResolver,
+		},
 	}
 
 	for _, tt := range cases {
@@ -157,8 +173,256 @@ func TestResolve(t *testing.T) {
 			rs = resolver
 		}
 
-		acc := make(LemmaAccumulator)
+		acc := NewLemmaAccumulator()
+		rs.Resolve(tt.in, acc, max)
+		assert.Equal(tt.out, acc.lemmata(max), tt.msg)
+	}
+
+	acc := NewLemmaAccumulator()
+	allResolver.Resolve("striping", acc, 1)
+	assert.Equal([]Lemma{{Val: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}}, acc.lemmata(10),
+		"Expect all matches limited by max")
+}
+
+func TestAffixRules(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := []Rule{
+		{
+			// German participle: gemacht -> machen
+			Kind:     AffixCircumfix,
+			Affix:    "ge",
+			EndAffix: "t",
+			Pos:      []nlpgo.POSId{nlpgo.PosIdVbn},
+			Transforms: []RuleTransform{
+				{HeadCutoff: 2, Cutoff: 1, Augment: "en"},
+				{HeadCutoff: 2, Cutoff: 1, Augment: "n"},
+			},
+		},
+		{
+			// German infinitive with zu: aufzumachen -> aufmachen
+			Kind:       AffixInfix,
+			Affix:      "zu",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVerb},
+			Transforms: []RuleTransform{{MinValidLen: 7}},
+		},
+		{
+			// Russian perfective prefix: поговорить -> говорить
+			Kind:       AffixPrefix,
+			Affix:      "по",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVerb},
+			Transforms: []RuleTransform{{HeadCutoff: 2}},
+		},
+		{
+			// unhappy -> happy
+			Kind:       AffixPrefix,
+			Affix:      "un",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdAdj},
+			Transforms: []RuleTransform{{HeadCutoff: 2, MinValidLen: 5}},
+		},
+		{
+			// rewrite -> write
+			Kind:  AffixPrefix,
+			Affix: "re",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVerb},
+			Transforms: []RuleTransform{
+				{HeadCutoff: 2, ReAfter: regexp.MustCompile(`^[^aeiou]`)},
+			},
+		},
+		{
+			Affix:      "s",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbz},
+			Transforms: []RuleTransform{{Cutoff: 1}},
+		},
+	}
+	lmChecker := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"machen":    {4},
+		"wandern":   {4},
+		"aufmachen": {4},
+		"говорить":  {4},
+		"happy":     {3},
+		"write":     {4},
+		"act":       {4},
+	})
+
+	resolver := NewAffixRuleResolver(rules, lmChecker)
+	sfxResolver := NewSuffixRuleResolver(rules, lmChecker)
+
+	cases := []struct {
+		in  string
+		out []Lemma
+		msg string
+		r   LmResolver
+	}{
+		{
+			in:  "gemacht",
+			out: []Lemma{{Val: "machen", Pos: []nlpgo.POSId{nlpgo.PosIdVbn}}},
+			msg: "Expect circumfix gemacht -> machen",
+		},
+		{
+			in:  "gewandert",
+			out: []Lemma{{Val: "wandern", Pos: []nlpgo.POSId{nlpgo.PosIdVbn}}},
+			msg: "Expect circumfix gewandert -> wandern",
+		},
+		{
+			in:  "get",
+			out: nil,
+			msg: "Expect circumfix does not match the whole word",
+		},
+		{
+			in:  "aufzumachen",
+			out: []Lemma{{Val: "aufmachen", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}},
+			msg: "Expect infix aufzumachen -> aufmachen",
+		},
+		{
+			in:  "zumachen",
+			out: nil,
+			msg: "Expect infix does not match the word start",
+		},
+		{
+			in:  "поговорить",
+			out: []Lemma{{Val: "говорить", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}},
+			msg: "Expect prefix поговорить -> говорить",
+		},
+		{
+			in:  "unhappy",
+			out: []Lemma{{Val: "happy", Pos: []nlpgo.POSId{nlpgo.PosIdAdj}}},
+			msg: "Expect prefix unhappy -> happy",
+		},
+		{
+			in:  "rewrite",
+			out: []Lemma{{Val: "write", Pos: []nlpgo.POSId{nlpgo.PosIdVerb}}},
+			msg: "Expect prefix rewrite -> write",
+		},
+		{
+			in:  "react",
+			out: nil,
+			msg: "Expect react is not re- + act because of ReAfter",
+		},
+		{
+			in:  "gemacht",
+			out: nil,
+			msg: "Expect non-suffix rules skipped by suffix resolver",
+			r:   sfxResolver,
+		},
+		{
+			in:  "acts",
+			out: []Lemma{{Val: "act", Pos: []nlpgo.POSId{nlpgo.PosIdVbz}}},
+			msg: "Expect suffix rules applied by suffix resolver",
+			r:   sfxResolver,
+		},
+	}
+
+	for _, tt := range cases {
+		const max = 10
+		rs := tt.r
+
+		if rs == nil {
+			rs = resolver
+		}
+
+		acc := NewLemmaAccumulator()
 		rs.Resolve(tt.in, acc, max)
 		assert.Equal(tt.out, acc.lemmata(max), tt.msg)
 	}
+
+	assert.Equal("circumfix", AffixCircumfix.String())
+}
+
+func TestSubstTransforms(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := []Rule{
+		{
+			// German umlaut plurals: väter -> vater, brüder -> bruder
+			Affix: "er",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{
+				{
+					Subst:   regexp.MustCompile(`ä([^aeiouäöü]+er)$`),
+					Replace: "a$1",
+				},
+				{
+					Subst:   regexp.MustCompile(`ü([^aeiouäöü]+er)$`),
+					Replace: "u$1",
+				},
+			},
+		},
+		{
+			// Compounds: gentlemenfarmers -> gentlemanfarmer
+			Kind:  AffixInfix,
+			Affix: "men",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{
+				{
+					Subst:   regexp.MustCompile(`^(\pL*)men(\pL+)s$`),
+					Replace: "${1}man${2}",
+				},
+			},
+		},
+	}
+	lmChecker := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"vater":           {2},
+		"bruder":          {2},
+		"gentlemanfarmer": {2},
+		"eiffeler":        {2},
+	})
+	resolver := NewAffixRuleResolver(rules, lmChecker)
+
+	cases := []struct {
+		in  string
+		out []Lemma
+		msg string
+	}{
+		{
+			in:  "väter",
+			out: []Lemma{{Val: "vater", Pos: []nlpgo.POSId{nlpgo.PosIdNns}}},
+			msg: "Expect väter -> vater",
+		},
+		{
+			in:  "brüder",
+			out: []Lemma{{Val: "bruder", Pos: []nlpgo.POSId{nlpgo.PosIdNns}}},
+			msg: "Expect brüder -> bruder",
+		},
+		{
+			in:  "eiffeler",
+			out: nil,
+			msg: "Expect no stem change without umlaut",
+		},
+		{
+			in:  "gentlemenfarmers",
+			out: []Lemma{{Val: "gentlemanfarmer", Pos: []nlpgo.POSId{nlpgo.PosIdNns}}},
+			msg: "Expect men -> man in the middle of the compound",
+		},
+		{
+			in:  "gentlemenfarmer",
+			out: nil,
+			msg: "Expect no lemma for Subst mismatch",
+		},
+		{
+			in:  "sportsmenfarmers",
+			out: nil,
+			msg: "Expect no lemma for unknown stem",
+		},
+	}
+
+	for _, tt := range cases {
+		acc := NewLemmaAccumulator()
+		resolver.Resolve(tt.in, acc, 10)
+		assert.Equal(tt.out, acc.lemmata(10), tt.msg)
+	}
+
+	l := NewLemmatizer(lmChecker, []LmResolver{resolver})
+	cc, steps := l.Explain("eiffeler", 0)
+	assert.Equal([]Candidate{{
+		Lemma:        Lemma{Val: "eiffeler", Pos: []nlpgo.POSId{2}},
+		Surface:      "eiffeler",
+		Source:       SourceLemma,
+		Resolver:     -1,
+		Transform:    -1,
+		InputIsLemma: true,
+		Score:        ScoreLemma,
+	}}, cc)
+	assert.Equal(RejectSubst, steps[2].Reject)
+	assert.Equal(RejectSubst, steps[3].Reject)
 }
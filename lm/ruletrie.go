@@ -0,0 +1,345 @@
+package lm
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// suffixNode is a node of the reversed suffix trie: the path from the root
+// spells the affixes backwards
+type suffixNode struct {
+	next []suffixEdge
+	// Indexes of the rules with the affix ending at the node, in the rule
+	// order
+	rules []int
+}
+
+type suffixEdge struct {
+	b byte
+	n *suffixNode
+}
+
+func (n *suffixNode) child(b byte) *suffixNode {
+	for _, e := range n.next {
+		if e.b == b {
+			return e.n
+		}
+	}
+	return nil
+}
+
+type trieRuleResolver struct {
+	ruleResolver
+	root suffixNode
+	// The ReBefore matchers per rule and transform
+	before [][]func(string) bool
+}
+
+// NewSuffixTrieResolver creates a resolver applying the suffix rules like
+// NewSuffixRuleResolver, but the rules are compiled into a reversed suffix trie
+// so only the rules whose affix matches the word are visited. The rules are
+// applied the longest affix first, the rules of the same affix keep their
+// order. The simple ReBefore regexps anchored at the word end (e.g.
+// `.[aeiou](bb|dd)ing$`) are matched backwards without the regexp engine.
+// The rules of other kinds are skipped.
+func NewSuffixTrieResolver(rules []Rule, lc LmChecker, opts ...RuleOption) LmResolver {
+	tr := &trieRuleResolver{ruleResolver: ruleResolver{rs: rules, lc: lc}}
+	for _, opt := range opts {
+		opt(&tr.ruleResolver)
+	}
+
+	tr.before = make([][]func(string) bool, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		if r.Kind != AffixSuffix {
+			continue
+		}
+
+		n := &tr.root
+		for j := len(r.Affix) - 1; j >= 0; j-- {
+			next := n.child(r.Affix[j])
+			if next == nil {
+				next = &suffixNode{}
+				n.next = append(n.next, suffixEdge{b: r.Affix[j], n: next})
+			}
+			n = next
+		}
+		n.rules = append(n.rules, i)
+
+		tr.before[i] = make([]func(string) bool, len(r.Transforms))
+		for ti, rt := range r.Transforms {
+			tr.before[i][ti] = compileBefore(rt.ReBefore)
+		}
+	}
+
+	return tr
+}
+
+func (tr *trieRuleResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
+	// If no rules are specified yield no lemma candidates
+	if tr.rs == nil || word == "" {
+		return
+	}
+
+	// If no lemma cheker, consider the word is lemma
+	if tr.lc == nil {
+		if _, ok := acc.FilterPos(nil); ok {
+			acc.Set(word, nil)
+		}
+		return
+	}
+
+	// Collect the nodes of the affixes matching the word end. The affix must
+	// leave at least one char of the word.
+	var buf [8]*suffixNode
+	path := buf[:0]
+	n := &tr.root
+	for i := len(word) - 1; ; i-- {
+		if len(n.rules) > 0 {
+			path = append(path, n)
+		}
+		if i <= 0 {
+			break
+		}
+		if n = n.child(word[i]); n == nil {
+			break
+		}
+	}
+	if len(path) == 0 {
+		return
+	}
+
+	wdRuneLen := utf8.RuneCountInString(word)
+
+	// The longest affix first
+	for i := len(path) - 1; i >= 0; i-- {
+		for _, ri := range path[i].rules {
+			r := &tr.rs[ri]
+			if tr.apply(word, wdRuneLen, r, len(word)-len(r.Affix), tr.before[ri], acc, max) {
+				return
+			}
+		}
+	}
+}
+
+// compileBefore returns a func equivalent to re.MatchString. If the regexp is
+// anchored at the end and built of literals, char classes and alternations
+// only, the func matches the word backwards without the regexp engine.
+// It returns nil for a nil regexp.
+func compileBefore(re *regexp.Regexp) func(string) bool {
+	if re == nil {
+		return nil
+	}
+
+	sre, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil || sre.Op != syntax.OpConcat || len(sre.Sub) < 2 ||
+		sre.Sub[len(sre.Sub)-1].Op != syntax.OpEndText {
+		return re.MatchString
+	}
+
+	tm, ok := compileTail(sre.Sub[:len(sre.Sub)-1], true)
+	if !ok {
+		return re.MatchString
+	}
+
+	return func(word string) bool {
+		return tm.match(word, len(word))
+	}
+}
+
+// tailNode is a step of a tailMatcher: a literal, a char class, or an
+// alternation of matchers without alternations
+type tailNode struct {
+	op   syntax.Op
+	lit  string
+	re   *syntax.Regexp
+	alts []tailMatcher
+	// The ASCII chars of the char class, or the last chars of the alternation
+	// branches if lastKnown
+	ascii     [128]bool
+	lastKnown bool
+}
+
+// tailMatcher matches a sequence of nodes at the end of a (sub)string
+type tailMatcher []tailNode
+
+// compileTail flattens the regexp nodes to a tailMatcher, ok is false if a node
+// is not supported. The alternations are not supported if alt is false.
+func compileTail(nodes []*syntax.Regexp, alt bool) (tm tailMatcher, ok bool) {
+	for _, n := range nodes {
+		switch n.Op {
+		case syntax.OpEmptyMatch:
+		case syntax.OpLiteral:
+			if n.Flags&syntax.FoldCase != 0 {
+				return nil, false
+			}
+			tm = append(tm, tailNode{op: n.Op, lit: string(n.Rune)})
+		case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			tn := tailNode{op: n.Op, re: n}
+			for j := 0; j+1 < len(n.Rune); j += 2 {
+				for r := n.Rune[j]; r <= n.Rune[j+1] && r < utf8.RuneSelf; r++ {
+					tn.ascii[r] = true
+				}
+			}
+			tm = append(tm, tn)
+		case syntax.OpCapture:
+			sub, ok := compileTail(n.Sub, alt)
+			if !ok {
+				return nil, false
+			}
+			tm = append(tm, sub...)
+		case syntax.OpConcat:
+			sub, ok := compileTail(n.Sub, alt)
+			if !ok {
+				return nil, false
+			}
+			tm = append(tm, sub...)
+		case syntax.OpAlternate:
+			if !alt {
+				return nil, false
+			}
+			tn := tailNode{op: n.Op, lastKnown: true}
+			for _, br := range n.Sub {
+				sub, ok := compileTail([]*syntax.Regexp{br}, false)
+				if !ok {
+					return nil, false
+				}
+				tn.alts = append(tn.alts, sub)
+				if tn.lastKnown {
+					tn.lastKnown = sub.lastChars(&tn.ascii)
+				}
+			}
+			tm = append(tm, tn)
+		default:
+			return nil, false
+		}
+	}
+
+	return tm, true
+}
+
+// match reports whether the matcher matches s[:end] at its end
+func (tm tailMatcher) match(s string, end int) bool {
+	for i := len(tm) - 1; i >= 0; i-- {
+		n := &tm[i]
+		if n.op != syntax.OpAlternate {
+			if end = n.matchEnd(s, end); end < 0 {
+				return false
+			}
+			continue
+		}
+
+		// Most of the words end with none of the branch last chars
+		if n.lastKnown && (end == 0 || s[end-1] >= utf8.RuneSelf || !n.ascii[s[end-1]]) {
+			return false
+		}
+
+		// The branches have no alternations, so each one matches at a single
+		// position at most
+		rest := tm[:i]
+		for _, br := range n.alts {
+			if e := br.matchEnd(s, end); e >= 0 && rest.match(s, e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// lastChars adds the ASCII chars the matcher match can end with to set. It
+// returns false if they are unknown, e.g. for a non-ASCII char.
+func (tm tailMatcher) lastChars(set *[128]bool) bool {
+	if len(tm) == 0 {
+		return false
+	}
+
+	n := &tm[len(tm)-1]
+	switch n.op {
+	case syntax.OpLiteral:
+		b := n.lit[len(n.lit)-1]
+		if b >= utf8.RuneSelf {
+			return false
+		}
+		set[b] = true
+	case syntax.OpCharClass:
+		for j := 0; j+1 < len(n.re.Rune); j += 2 {
+			if n.re.Rune[j+1] >= utf8.RuneSelf {
+				return false
+			}
+		}
+		for b, in := range n.ascii {
+			set[b] = set[b] || in
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// matchEnd returns the start index of the match ending at s[:end], or -1 if
+// none. The matcher must have no alternations.
+func (tm tailMatcher) matchEnd(s string, end int) int {
+	for i := len(tm) - 1; i >= 0 && end >= 0; i-- {
+		end = tm[i].matchEnd(s, end)
+	}
+	return end
+}
+
+// matchEnd returns the start index of the node match ending at s[:end], or -1
+// if none
+func (n *tailNode) matchEnd(s string, end int) int {
+	if n.op == syntax.OpLiteral {
+		// The literals are short, the loop is faster than strings.HasSuffix
+		if end < len(n.lit) {
+			return -1
+		}
+		for i := len(n.lit) - 1; i >= 0; i-- {
+			if end--; s[end] != n.lit[i] {
+				return -1
+			}
+		}
+		return end
+	}
+
+	if end == 0 {
+		return -1
+	}
+	if b := s[end-1]; b < utf8.RuneSelf {
+		switch n.op {
+		case syntax.OpAnyCharNotNL:
+			if b == '\n' {
+				return -1
+			}
+		case syntax.OpCharClass:
+			if !n.ascii[b] {
+				return -1
+			}
+		}
+		return end - 1
+	}
+	r, size := utf8.DecodeLastRuneInString(s[:end])
+
+	switch n.op {
+	case syntax.OpAnyCharNotNL:
+		if r == '\n' {
+			return -1
+		}
+	case syntax.OpCharClass:
+		in := false
+		for j := 0; j+1 < len(n.re.Rune); j += 2 {
+			if r >= n.re.Rune[j] && r <= n.re.Rune[j+1] {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return -1
+		}
+	}
+
+	return end - size
+}
@@ -0,0 +1,178 @@
+package lm
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestSuffixTrieResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := []Rule{
+		{
+			Affix: "s",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{
+				{Cutoff: 1, ReBefore: regexp.MustCompile(`.[^s]s$`)},
+			},
+		},
+		{
+			Affix: "ing",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbg},
+			Transforms: []RuleTransform{
+				{Cutoff: 3, Augment: "e", MinValidLen: 5},
+				{Cutoff: 4, ReBefore: regexp.MustCompile(`.[aeiou](bb|pp)ing$`), MinValidLen: 6},
+				{Cutoff: 3, MinValidLen: 5},
+			},
+		},
+		{
+			Kind:       AffixPrefix,
+			Affix:      "un",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdAdj},
+			Transforms: []RuleTransform{{HeadCutoff: 2}},
+		},
+		{
+			Affix: "es",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdNns},
+			Transforms: []RuleTransform{
+				{Cutoff: 2, ReBefore: regexp.MustCompile(`(ch|sh|x)es$`)},
+			},
+		},
+		{
+			Affix:      "л",
+			Pos:        []nlpgo.POSId{nlpgo.PosIdVbd},
+			Transforms: []RuleTransform{{Cutoff: 1, Augment: "ть"}},
+		},
+	}
+	lmChecker := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"box":     {2},
+		"boxe":    {2},
+		"cat":     {2},
+		"strip":   {4},
+		"stripe":  {4},
+		"happy":   {3},
+		"слушать": {4},
+	})
+
+	linear := NewSuffixRuleResolver(rules, lmChecker, WithAllMatches())
+	trie := NewSuffixTrieResolver(rules, lmChecker, WithAllMatches())
+
+	cases := []struct {
+		in  string
+		out []Lemma
+		msg string
+	}{
+		{
+			in: "boxes",
+			out: []Lemma{
+				{Val: "box", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+				{Val: "boxe", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			},
+			msg: "Expect the longest affix first",
+		},
+		{
+			in:  "cats",
+			out: []Lemma{{Val: "cat", Pos: []nlpgo.POSId{nlpgo.PosIdNns}}},
+			msg: "Expect cats -> cat",
+		},
+		{
+			in: "stripping",
+			out: []Lemma{
+				{Val: "strip", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+			},
+			msg: "Expect stripping -> strip",
+		},
+		{
+			in: "striping",
+			out: []Lemma{
+				{Val: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+				{Val: "strip", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+			},
+			msg: "Expect striping -> stripe, strip",
+		},
+		{
+			in:  "unhappy",
+			out: nil,
+			msg: "Expect prefix rules skipped",
+		},
+		{
+			in:  "слушал",
+			out: []Lemma{{Val: "слушать", Pos: []nlpgo.POSId{nlpgo.PosIdVbd}}},
+			msg: "Expect multibyte affixes matched",
+		},
+		{
+			in:  "s",
+			out: nil,
+			msg: "Expect the affix leaves a char of the word",
+		},
+	}
+
+	for _, tt := range cases {
+		const max = 10
+
+		acc := NewLemmaAccumulator()
+		trie.Resolve(tt.in, acc, max)
+		assert.Equal(tt.out, acc.lemmata(max), tt.msg)
+	}
+
+	// The same result as the linear resolver if the affixes don't overlap
+	for _, w := range []string{"cats", "stripping", "striping", "unhappy", "слушал", "s", "", "ing"} {
+		const max = 10
+
+		want, got := NewLemmaAccumulator(), NewLemmaAccumulator()
+		linear.Resolve(w, want, max)
+		trie.Resolve(w, got, max)
+		assert.Equal(want.candidates(max), got.candidates(max), w)
+	}
+
+	acc := NewLemmaAccumulator()
+	NewSuffixTrieResolver(nil, lmChecker).Resolve("cats", acc, 10)
+	assert.Empty(acc.lemmata(10), "Expect no lemma without rules")
+
+	acc = NewLemmaAccumulator()
+	NewSuffixTrieResolver(rules, nil).Resolve("cats", acc, 10)
+	assert.Equal([]Lemma{{Val: "cats"}}, acc.lemmata(10), "Expect input not modified without lemma checker")
+}
+
+func TestCompileBefore(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(compileBefore(nil))
+
+	words := []string{
+		"", "s", "es", "ches", "aches", "boxes", "stripping", "striping",
+		"tying", "ying", "hotter", "easier", "faked", "tried", "ied",
+		"слушал", "слушаешь", "a\nes", "\xffes", "FAKED", "шes", "xes", "zes", "aes",
+	}
+
+	for _, expr := range []string{
+		`.ches$`,
+		`.[^zs']s$`,
+		`.[aeiou](bb|cc|dd|pp|tt)ing$`,
+		`.ying$`,
+		`..cked$`,
+		`(ch|sh|x)es$`,
+		`(?:)es$`,
+		`(?i)faked$`,
+		`[a-z]+ed$`,
+		`^.ied$`,
+		`ше?шь$`,
+		`(s|(x|z))es$`,
+		`(ш|[xz])es$`,
+		`([xz]|s)es$`,
+		`(a|)es$`,
+		`(ch|[^a])es$`,
+		`\pL\z`,
+		`(?s).es$`,
+		`ing`,
+	} {
+		re := regexp.MustCompile(expr)
+		match := compileBefore(re)
+		for _, w := range words {
+			assert.Equal(re.MatchString(w), match(w), "%s %q", expr, w)
+		}
+	}
+}
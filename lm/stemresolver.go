@@ -0,0 +1,39 @@
+package lm
+
+// Stemmer returns the stem of the word, e.g. "generously" -> "generous"
+type Stemmer func(word string) string
+
+type stemResolver struct {
+	stem Stemmer
+}
+
+// NewStemResolver creates a fallback resolver which adds the stem of the word
+// if no preceding resolver (nor the input lookup) has added a candidate, so
+// it's to be the last one in the resolver list. The stem is not checked with
+// the LmChecker, it's added with SourceStem and ScoreStem to tell it from the
+// lemmata. The stem has no POS and is added regardless of the POS constraint.
+func NewStemResolver(stem Stemmer) LmResolver {
+	return stemResolver{stem: stem}
+}
+
+// Resolve adds the stem of the word if acc is empty
+func (r stemResolver) Resolve(word string, acc *LemmaAccumulator, max int) {
+	if acc.Len() > 0 {
+		return
+	}
+
+	st := r.stem(word)
+	if st == "" {
+		return
+	}
+	if acc.Tracing() {
+		acc.Trace(TraceStep{Kind: StepStem, Word: word, Transform: -1, Candidate: st})
+	}
+
+	acc.Add(Candidate{
+		Lemma:     Lemma{Val: st},
+		Source:    SourceStem,
+		Transform: -1,
+		Score:     ScoreStem,
+	})
+}
@@ -0,0 +1,69 @@
+package lm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestStemResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"cat": {nlpgo.PosIdNoun},
+	})
+	rules := []Rule{
+		{Affix: "s", Pos: []nlpgo.POSId{nlpgo.PosIdNns}, Transforms: []RuleTransform{{Cutoff: 1}}},
+	}
+	stem := func(word string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(word, "s"), "ed")
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{
+		NewSuffixRuleResolver(rules, lmIdx),
+		NewStemResolver(stem),
+	})
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "cat", Pos: []nlpgo.POSId{nlpgo.PosIdNns}},
+			Surface:   "cats",
+			Source:    SourceRule,
+			Affix:     "s",
+			Transform: 0,
+			Score:     ScoreRule,
+		},
+	}, l.Candidates("cats", 5), "No stem if a lemma is found")
+
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "zoom"},
+			Surface:   "zoomed",
+			Source:    SourceStem,
+			Resolver:  1,
+			Transform: -1,
+			Score:     ScoreStem,
+		},
+	}, l.Candidates("zoomed", 5))
+
+	assert.Equal([]Lemma{{Val: "zoom"}}, l.LemmaCandidatesFor("zoomed", []nlpgo.POSId{nlpgo.PosIdVbd}, 5),
+		"The stem ignores the POS constraint")
+	assert.Equal("stem", SourceStem.String())
+
+	cc, steps := l.Explain("dogs", 5)
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "dog"},
+			Surface:   "dogs",
+			Source:    SourceStem,
+			Resolver:  1,
+			Transform: -1,
+			Score:     ScoreStem,
+		},
+	}, cc)
+	assert.Contains(steps, TraceStep{Kind: StepStem, Resolver: 1, Word: "dogs", Transform: -1, Candidate: "dog"})
+
+	empty := NewLemmatizer(lmIdx, []LmResolver{NewStemResolver(func(string) string { return "" })})
+	assert.Nil(empty.Candidates("dogs", 5))
+}
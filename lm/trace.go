@@ -0,0 +1,175 @@
+package lm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timurgarif/nlpgo"
+)
+
+// StepKind identifies a step of the lemmatization pipeline
+type StepKind uint8
+
+const (
+	// Lookup of the input word in the LmChecker
+	StepLookup StepKind = iota
+	// Lookup of the input word in an exception index
+	StepException
+	// A Rule whose affix matched the word
+	StepRule
+	// A RuleTransform application attempt
+	StepTransform
+	// A candidate chosen as a result, in the rank order
+	StepChoice
+	// The stem fallback (see NewStemResolver)
+	StepStem
+)
+
+var stepKindNames = [...]string{
+	StepLookup:    "lookup",
+	StepException: "exception",
+	StepRule:      "rule",
+	StepTransform: "transform",
+	StepChoice:    "choice",
+	StepStem:      "stem",
+}
+
+func (k StepKind) String() string {
+	if int(k) < len(stepKindNames) {
+		return stepKindNames[k]
+	}
+	return "unknown"
+}
+
+// Reject is a reason why a step yields no candidate
+type Reject uint8
+
+const (
+	RejectNone Reject = iota
+	// The word is shorter than RuleTransform.MinValidLen
+	RejectMinValidLen
+	// RuleTransform.Cutoff leaves nothing of the word
+	RejectCutoff
+	// The word does not match RuleTransform.ReBefore
+	RejectReBefore
+	// The candidate does not match RuleTransform.ReAfter
+	RejectReAfter
+	// The LmChecker or exception index has no such entry
+	RejectLookup
+	// The POS'es do not match the rule forms or the POS constraint
+	RejectPos
+	// The word does not match RuleTransform.Subst
+	RejectSubst
+	// The guessed candidate is not plausible (see NewGuessResolver)
+	RejectImplausible
+)
+
+var rejectNames = [...]string{
+	RejectNone:        "",
+	RejectMinValidLen: "shorter than MinValidLen",
+	RejectCutoff:      "cutoff exceeds word length",
+	RejectReBefore:    "ReBefore mismatch",
+	RejectReAfter:     "ReAfter mismatch",
+	RejectLookup:      "lookup miss",
+	RejectPos:         "POS mismatch",
+	RejectSubst:       "Subst mismatch",
+	RejectImplausible: "implausible",
+}
+
+func (r Reject) String() string {
+	if int(r) < len(rejectNames) {
+		return rejectNames[r]
+	}
+	return "unknown"
+}
+
+// TraceStep describes a single step of the lemmatization pipeline
+type TraceStep struct {
+	Kind StepKind
+	// Index of the resolver in the Lemmatizer resolver list, -1 for the input
+	// lookup. For the choice steps it's the index of the resolver which
+	// produced the chosen candidate (-1 if it's the input word itself).
+	Resolver int
+	// The input word
+	Word string
+	// Affix of the Rule (StepRule and StepTransform only)
+	Affix string
+	// Index of the RuleTransform within Rule.Transforms, -1 if not applicable
+	Transform int
+	// The lemma candidate examined (if any)
+	Candidate string
+	// The candidate POS'es (if any)
+	Pos []nlpgo.POSId
+	// Why the step yields no candidate, RejectNone if it does
+	Reject Reject
+}
+
+func (s TraceStep) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %q", s.Kind, s.Word)
+	if s.Affix != "" {
+		fmt.Fprintf(&b, " affix %q", s.Affix)
+	}
+	if s.Transform >= 0 {
+		fmt.Fprintf(&b, " transform #%d", s.Transform)
+	}
+	if s.Candidate != "" {
+		fmt.Fprintf(&b, " -> %q", s.Candidate)
+	}
+	if len(s.Pos) > 0 {
+		fmt.Fprintf(&b, " %v", s.Pos)
+	}
+	if s.Reject != RejectNone {
+		fmt.Fprintf(&b, ": %s", s.Reject)
+	}
+
+	return b.String()
+}
+
+// Tracer receives the steps of the lemmatization pipeline
+type Tracer interface {
+	Trace(step TraceStep)
+}
+
+// TracerFunc is an adapter to use a function as a Tracer
+type TracerFunc func(step TraceStep)
+
+func (f TracerFunc) Trace(step TraceStep) {
+	f(step)
+}
+
+// Explain resolves up to `max` candidates for the word like Candidates and
+// records every step taken to get them.
+// The result cache is bypassed.
+func (l Lemmatizer) Explain(word string, max int) (candidates []Candidate, steps []TraceStep) {
+	if max <= 0 {
+		max = l.max
+	}
+
+	candidates = l.resolve(l.Normalize(word), nil, max, TracerFunc(func(s TraceStep) {
+		steps = append(steps, s)
+	}))
+	l.setSurface(candidates, word)
+
+	return
+}
+
+// Tracing reports whether the steps of the current lemmatization call are
+// traced. Resolvers may use it to skip building the trace steps.
+func (acc *LemmaAccumulator) Tracing() bool {
+	return acc.tracer != nil
+}
+
+// Trace reports a step of the current lemmatization call if tracing is on.
+// The Resolver field is filled in by the accumulator.
+func (acc *LemmaAccumulator) Trace(step TraceStep) {
+	if acc.tracer == nil {
+		return
+	}
+
+	if step.Kind != StepChoice {
+		step.Resolver = acc.resolver
+	}
+	acc.tracer.Trace(step)
+}
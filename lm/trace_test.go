@@ -0,0 +1,109 @@
+package lm
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timurgarif/nlpgo"
+)
+
+func TestExplain(t *testing.T) {
+	assert := assert.New(t)
+
+	lmIdx := NewLemmaIndex(map[string][]nlpgo.POSId{
+		"strip":  {4, 2},
+		"stripe": {2, 4},
+	})
+	rules := []Rule{
+		{
+			Affix: "ed",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbd},
+		},
+		{
+			Affix: "ing",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdVbg},
+			Transforms: []RuleTransform{
+				{
+					Cutoff:      4,
+					ReBefore:    regexp.MustCompile(`.[aeiou](pp|tt)ing$`),
+					MinValidLen: 6,
+				},
+				{
+					Cutoff:      3,
+					MinValidLen: 12,
+				},
+				{
+					Cutoff:  3,
+					Augment: "e",
+					ReAfter: regexp.MustCompile(`pe$`),
+				},
+			},
+		},
+	}
+	l := NewLemmatizer(lmIdx, []LmResolver{
+		NewExceptionResolver(map[string][]Lemma{}),
+		NewSuffixRuleResolver(rules, lmIdx),
+	})
+
+	cc, steps := l.Explain("striping", 5)
+	assert.Equal([]Candidate{
+		{
+			Lemma:     Lemma{Val: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+			Surface:   "striping",
+			Source:    SourceRule,
+			Resolver:  1,
+			Affix:     "ing",
+			Transform: 2,
+			Score:     ScoreRuleContext,
+		},
+	}, cc)
+	assert.Equal([]TraceStep{
+		{Kind: StepLookup, Resolver: -1, Word: "striping", Transform: -1, Reject: RejectLookup},
+		{Kind: StepException, Resolver: 0, Word: "striping", Transform: -1, Reject: RejectLookup},
+		{Kind: StepRule, Resolver: 1, Word: "striping", Affix: "ing", Transform: -1, Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+		{Kind: StepTransform, Resolver: 1, Word: "striping", Affix: "ing", Transform: 0, Reject: RejectReBefore},
+		{Kind: StepTransform, Resolver: 1, Word: "striping", Affix: "ing", Transform: 1, Reject: RejectMinValidLen},
+		{Kind: StepTransform, Resolver: 1, Word: "striping", Affix: "ing", Transform: 2, Candidate: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+		{Kind: StepChoice, Resolver: 1, Word: "striping", Affix: "ing", Transform: 2, Candidate: "stripe", Pos: []nlpgo.POSId{nlpgo.PosIdVbg}},
+	}, steps)
+
+	_, steps = l.Explain("stripping", 5)
+	assert.Equal(TraceStep{
+		Kind:      StepTransform,
+		Resolver:  1,
+		Word:      "stripping",
+		Affix:     "ing",
+		Transform: 0,
+		Candidate: "strip",
+		Pos:       []nlpgo.POSId{nlpgo.PosIdVbg},
+	}, steps[3])
+	assert.Equal(`transform "stripping" affix "ing" transform #0 -> "strip" [46]`, steps[3].String())
+
+	cc, steps = l.Explain("strappe", 5)
+	assert.Empty(cc)
+	assert.Len(steps, 2)
+
+	// Lookup miss, POS mismatch and ReAfter mismatch
+	var traced []TraceStep
+	l = NewLemmatizer(lmIdx, []LmResolver{NewSuffixRuleResolver([]Rule{
+		{
+			Affix: "ing",
+			Pos:   []nlpgo.POSId{nlpgo.PosIdJjr},
+			Transforms: []RuleTransform{
+				{Cutoff: 4},
+				{Cutoff: 3},
+				{Cutoff: 3, Augment: "e", ReAfter: regexp.MustCompile(`^x`)},
+			},
+		},
+	}, lmIdx)}, WithTracer(TracerFunc(func(s TraceStep) {
+		traced = append(traced, s)
+	})))
+	assert.Empty(l.Candidates("striping", 5))
+	assert.Len(traced, 5)
+	assert.Equal(RejectLookup, traced[2].Reject)
+	assert.Equal(RejectPos, traced[3].Reject)
+	assert.Equal(RejectReAfter, traced[4].Reject)
+	assert.Equal("stripe", traced[4].Candidate)
+	assert.Equal(`transform "striping" affix "ing" transform #2 -> "stripe": ReAfter mismatch`, traced[4].String())
+}
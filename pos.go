@@ -1,6 +1,11 @@
 // Package nlpgo provides basic NLP defs used in sub-packages.
 package nlpgo
 
+import (
+	"fmt"
+	"strconv"
+)
+
 type POS string
 
 // A compact encoding of POS value
@@ -48,7 +53,59 @@ const (
 	PosVbz POS = "VBZ"
 )
 
-var posForms = map[POSId]POSId{
+var posNames = map[POSId]POS{
+	PosIdNoun: PosNoun,
+	PosIdAdj:  PosAdj,
+	PosIdVerb: PosVerb,
+	PosIdPron: PosPron,
+	PosIdNum:  PosNum,
+	PosIdAdv:  PosAdv,
+
+	PosIdNns: PosNns,
+	PosIdJjr: PosJjr,
+	PosIdJjs: PosJjs,
+	PosIdRbr: PosRbr,
+	PosIdRbs: PosRbs,
+	PosIdVbd: PosVbd,
+	PosIdVbn: PosVbn,
+	PosIdVbg: PosVbg,
+	PosIdVbp: PosVbp,
+	PosIdVbz: PosVbz,
+}
+
+var posIds = func() map[POS]POSId {
+	m := make(map[POS]POSId, len(posNames))
+	for id, name := range posNames {
+		m[name] = id
+	}
+	return m
+}()
+
+// POS returns the POS name of the id, empty string if the id is unknown
+func (p POSId) POS() POS {
+	return posNames[p]
+}
+
+// Id returns the POSId of the POS name, zero value if the name is unknown
+func (p POS) Id() POSId {
+	return posIds[p]
+}
+
+// ParsePOS returns the POSId of a POS name (e.g. "VBD") or a numeric id (e.g.
+// "44"), an error if s is neither
+func ParsePOS(s string) (POSId, error) {
+	if p := POS(s).Id(); p != 0 {
+		return p, nil
+	}
+	id, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid POS %q", s)
+	}
+	return POSId(id), nil
+}
+
+// The base POS'es indexed by the form, 0 for the non-form ids
+var posForms = [256]POSId{
 	PosIdNns: PosIdNoun,
 	PosIdJjr: PosIdAdj,
 	PosIdJjs: PosIdAdj,
@@ -62,8 +119,30 @@ var posForms = map[POSId]POSId{
 }
 
 func (p POSId) HasForm(f POSId) bool {
-	if pos, ok := posForms[f]; ok {
-		return pos == p
+	pos := posForms[f]
+	return pos != 0 && pos == p
+}
+
+// The forms in the POSId order
+var formIds = []POSId{
+	PosIdNns,
+	PosIdJjr,
+	PosIdJjs,
+	PosIdRbr,
+	PosIdRbs,
+	PosIdVbd,
+	PosIdVbn,
+	PosIdVbg,
+	PosIdVbp,
+	PosIdVbz,
+}
+
+// Forms returns the forms of the POS, e.g. NNS for NOUN, nil if none
+func (p POSId) Forms() (ff []POSId) {
+	for _, f := range formIds {
+		if p.HasForm(f) {
+			ff = append(ff, f)
+		}
 	}
-	return false
+	return
 }
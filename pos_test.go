@@ -45,3 +45,49 @@ func TestPosHasForm(t *testing.T) {
 		assert.Equal(tt.out, tt.obj.HasForm(tt.subj), tt.msg)
 	}
 }
+
+func TestPosNames(t *testing.T) {
+	assert := assert.New(t)
+
+	for id := range posNames {
+		assert.Equal(id, id.POS().Id())
+	}
+
+	assert.Equal(PosVbz, PosIdVbz.POS())
+	assert.Equal(PosIdNoun, PosNoun.Id())
+	assert.Equal(POS(""), POSId(1).POS())
+	assert.Equal(POSId(0), POS("XYZ").Id())
+}
+
+func TestPosForms(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]POSId{PosIdNns}, PosIdNoun.Forms())
+	assert.Equal([]POSId{PosIdJjr, PosIdJjs}, PosIdAdj.Forms())
+	assert.Equal([]POSId{PosIdVbd, PosIdVbn, PosIdVbg, PosIdVbp, PosIdVbz}, PosIdVerb.Forms())
+	assert.Nil(PosIdPron.Forms())
+	assert.Nil(PosIdVbz.Forms())
+
+	var n int
+	for _, p := range posForms {
+		if p != 0 {
+			n++
+		}
+	}
+	assert.Len(formIds, n)
+}
+
+func TestParsePOS(t *testing.T) {
+	assert := assert.New(t)
+
+	for in, out := range map[string]POSId{"VBD": PosIdVbd, "NOUN": PosIdNoun, "44": PosIdVbd, "99": POSId(99)} {
+		p, err := ParsePOS(in)
+		assert.NoError(err, in)
+		assert.Equal(out, p, in)
+	}
+
+	for _, in := range []string{"", "0", "vbd", "256", "NNS,VBZ"} {
+		_, err := ParsePOS(in)
+		assert.EqualError(err, `invalid POS "`+in+`"`)
+	}
+}